@@ -5,4 +5,5 @@ go 1.12
 require (
 	github.com/bxcodec/faker/v3 v3.2.0
 	github.com/stretchr/testify v1.5.0
+	gopkg.in/yaml.v2 v2.2.2
 )
@@ -0,0 +1,52 @@
+// Package adf provides a minimal implementation of the Atlassian Document Format (ADF) which is required by the
+// version 3 of the Jira Cloud REST API for comments, descriptions and rich-text custom fields. It offers a small
+// markdown-ish converter to produce documents and a plain text extraction to read them back.
+package adf
+
+// Node types and mark types used by this package
+const (
+	TypeDoc         = "doc"
+	TypeParagraph   = "paragraph"
+	TypeHeading     = "heading"
+	TypeText        = "text"
+	TypeHardBreak   = "hardBreak"
+	TypeBulletList  = "bulletList"
+	TypeOrderedList = "orderedList"
+	TypeListItem    = "listItem"
+	TypeCodeBlock   = "codeBlock"
+	TypeMention     = "mention"
+
+	MarkStrong = "strong"
+	MarkCode   = "code"
+	MarkLink   = "link"
+)
+
+// Node is a single element of an ADF document. The document itself is a node of type 'doc'.
+type Node struct {
+	Version int                    `json:"version,omitempty"`
+	Type    string                 `json:"type"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []Node                 `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []Mark                 `json:"marks,omitempty"`
+}
+
+// Mark is a formatting applied to a text node (bold, code, link, ...).
+type Mark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// Returns an empty ADF document
+func NewDocument() Node {
+	return Node{Version: 1, Type: TypeDoc, Content: make([]Node, 0)}
+}
+
+// Returns the value of the string attribute of the node, or an empty string if it isn't set
+func (n Node) StringAttr(name string) string {
+	if val, ok := n.Attrs[name].(string); ok {
+		return val
+	}
+
+	return ""
+}
@@ -0,0 +1,179 @@
+package adf
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	headingLine     = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	bulletItemLine  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedItemLine = regexp.MustCompile(`^(\s*)\d+[.)]\s+(.*)$`)
+
+	// Inline elements, in order: mention, link, bold and inline code
+	inlineElement = regexp.MustCompile(`\[~accountid:([^\]]+)\]|\[([^\]]+)\]\(([^)\s]+)\)|\*\*([^*]+)\*\*|` + "`([^`]+)`")
+)
+
+// listLine is a single item of a list with its indentation
+type listLine struct {
+	indent  int
+	ordered bool
+	text    string
+}
+
+// Converts a markdown-ish text into an ADF document. The following elements are supported: paragraphs (separated by
+// a blank line), headings, bullet and ordered lists (nested by indentation), fenced code blocks, links written as
+// [text](url), mentions written as [~accountid:ID], **bold** text and `inline code`.
+func FromMarkdown(text string) Node {
+	doc := NewDocument()
+	lines := strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+	paragraph := make([]string, 0)
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			doc.Content = append(doc.Content, newParagraph(paragraph))
+			paragraph = make([]string, 0)
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flushParagraph()
+			code := make([]string, 0)
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			doc.Content = append(doc.Content, newCodeBlock(strings.TrimPrefix(trimmed, "```"), code))
+		case trimmed == "":
+			flushParagraph()
+		case headingLine.MatchString(trimmed):
+			flushParagraph()
+			m := headingLine.FindStringSubmatch(trimmed)
+			doc.Content = append(doc.Content, Node{
+				Type:    TypeHeading,
+				Attrs:   map[string]interface{}{"level": len(m[1])},
+				Content: parseInline(m[2]),
+			})
+		case isListLine(line):
+			flushParagraph()
+			items := make([]listLine, 0)
+			for ; i < len(lines) && isListLine(lines[i]); i++ {
+				items = append(items, toListLine(lines[i]))
+			}
+			i--
+			list, _ := newList(items, 0)
+			doc.Content = append(doc.Content, list)
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+
+	flushParagraph()
+
+	return doc
+}
+
+func newParagraph(lines []string) Node {
+	p := Node{Type: TypeParagraph, Content: make([]Node, 0)}
+	for index, line := range lines {
+		if index > 0 {
+			p.Content = append(p.Content, Node{Type: TypeHardBreak})
+		}
+		p.Content = append(p.Content, parseInline(line)...)
+	}
+
+	return p
+}
+
+func newCodeBlock(language string, lines []string) Node {
+	code := Node{Type: TypeCodeBlock}
+	if language = strings.TrimSpace(language); language != "" {
+		code.Attrs = map[string]interface{}{"language": language}
+	}
+
+	if len(lines) > 0 {
+		code.Content = []Node{{Type: TypeText, Text: strings.Join(lines, "\n")}}
+	}
+
+	return code
+}
+
+// Builds a list from the items starting at 'start' and returns the index of the first item that isn't part of it.
+// Items indented deeper than the first one are nested in the preceding list item.
+func newList(items []listLine, start int) (Node, int) {
+	list := Node{Type: TypeBulletList, Content: make([]Node, 0)}
+	if items[start].ordered {
+		list.Type = TypeOrderedList
+	}
+
+	indent := items[start].indent
+	i := start
+	for i < len(items) && items[i].indent >= indent {
+		if items[i].indent > indent && len(list.Content) > 0 {
+			var nested Node
+			nested, i = newList(items, i)
+			last := &list.Content[len(list.Content)-1]
+			last.Content = append(last.Content, nested)
+			continue
+		}
+
+		list.Content = append(list.Content, Node{
+			Type:    TypeListItem,
+			Content: []Node{{Type: TypeParagraph, Content: parseInline(items[i].text)}},
+		})
+		i++
+	}
+
+	return list, i
+}
+
+func isListLine(line string) bool {
+	return bulletItemLine.MatchString(line) || orderedItemLine.MatchString(line)
+}
+
+func toListLine(line string) listLine {
+	if m := orderedItemLine.FindStringSubmatch(line); m != nil {
+		return listLine{indent: len(m[1]), ordered: true, text: m[2]}
+	}
+
+	m := bulletItemLine.FindStringSubmatch(line)
+	return listLine{indent: len(m[1]), text: m[2]}
+}
+
+func parseInline(text string) []Node {
+	nodes := make([]Node, 0)
+	last := 0
+
+	for _, m := range inlineElement.FindAllStringSubmatchIndex(text, -1) {
+		if m[0] > last {
+			nodes = append(nodes, Node{Type: TypeText, Text: text[last:m[0]]})
+		}
+
+		switch {
+		case m[2] >= 0:
+			nodes = append(nodes, Node{Type: TypeMention, Attrs: map[string]interface{}{"id": text[m[2]:m[3]]}})
+		case m[4] >= 0:
+			nodes = append(nodes, Node{
+				Type:  TypeText,
+				Text:  text[m[4]:m[5]],
+				Marks: []Mark{{Type: MarkLink, Attrs: map[string]interface{}{"href": text[m[6]:m[7]]}}},
+			})
+		case m[8] >= 0:
+			nodes = append(nodes, Node{Type: TypeText, Text: text[m[8]:m[9]], Marks: []Mark{{Type: MarkStrong}}})
+		case m[10] >= 0:
+			nodes = append(nodes, Node{Type: TypeText, Text: text[m[10]:m[11]], Marks: []Mark{{Type: MarkCode}}})
+		}
+
+		last = m[1]
+	}
+
+	if last < len(text) {
+		nodes = append(nodes, Node{Type: TypeText, Text: text[last:]})
+	}
+
+	return nodes
+}
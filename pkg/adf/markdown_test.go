@@ -0,0 +1,75 @@
+package adf_test
+
+import (
+	"encoding/json"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/adf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFromMarkdown(t *testing.T) {
+	t.Run("paragraphs are separated by blank lines and lines by hard breaks", func(t *testing.T) {
+		// Act
+		doc := adf.FromMarkdown("first line\nsecond line\n\nsecond paragraph")
+
+		// Assert
+		require.Len(t, doc.Content, 2)
+		assert.Equal(t, adf.TypeParagraph, doc.Content[0].Type)
+		require.Len(t, doc.Content[0].Content, 3)
+		assert.Equal(t, adf.TypeHardBreak, doc.Content[0].Content[1].Type)
+		assert.Equal(t, "second paragraph", doc.Content[1].Content[0].Text)
+	})
+
+	t.Run("nested lists follow the indentation", func(t *testing.T) {
+		// Act
+		doc := adf.FromMarkdown("- one\n  1. nested\n- two")
+
+		// Assert
+		require.Len(t, doc.Content, 1)
+		list := doc.Content[0]
+		assert.Equal(t, adf.TypeBulletList, list.Type)
+		require.Len(t, list.Content, 2)
+		require.Len(t, list.Content[0].Content, 2)
+		assert.Equal(t, adf.TypeOrderedList, list.Content[0].Content[1].Type)
+	})
+
+	t.Run("fenced code keeps its content and language", func(t *testing.T) {
+		// Act
+		doc := adf.FromMarkdown("```go\nfmt.Println(\"**not bold**\")\n```")
+
+		// Assert
+		require.Len(t, doc.Content, 1)
+		assert.Equal(t, adf.TypeCodeBlock, doc.Content[0].Type)
+		assert.Equal(t, "go", doc.Content[0].StringAttr("language"))
+		assert.Equal(t, "fmt.Println(\"**not bold**\")", doc.Content[0].Content[0].Text)
+	})
+
+	t.Run("links and mentions are converted", func(t *testing.T) {
+		// Act
+		doc := adf.FromMarkdown("see [build](https://ci.local/1) [~accountid:5b10a2844c20165700ede21g]")
+
+		// Assert
+		nodes := doc.Content[0].Content
+		require.Len(t, nodes, 4)
+		assert.Equal(t, "https://ci.local/1", nodes[1].Marks[0].Attrs["href"])
+		assert.Equal(t, adf.TypeMention, nodes[3].Type)
+		assert.Equal(t, "5b10a2844c20165700ede21g", nodes[3].StringAttr("id"))
+	})
+}
+
+func TestRichText_RoundTrip(t *testing.T) {
+	// Arrange
+	text := "Deployed **1.2.0**\n\n- api\n- web"
+	b, err := json.Marshal(adf.NewRichText(text, true))
+	require.NoError(t, err)
+
+	// Act
+	var read adf.RichText
+	err = json.Unmarshal(b, &read)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, read.Document)
+	assert.Equal(t, "Deployed 1.2.0\n- api\n- web", read.String())
+}
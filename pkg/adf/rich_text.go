@@ -0,0 +1,59 @@
+package adf
+
+import (
+	"encoding/json"
+)
+
+// RichText holds the value of a rich-text element (comment body, description, rich-text custom field). With the
+// version 2 of the API the value is a plain string, with the version 3 it is an ADF document. Both representations
+// are accepted when unmarshalling.
+type RichText struct {
+	Text     string
+	Document *Node
+}
+
+// Returns a rich-text value from the text. The text is converted into an ADF document only if asDocument is true,
+// which is the case with the version 3 of the API.
+func NewRichText(text string, asDocument bool) RichText {
+	if asDocument {
+		doc := FromMarkdown(text)
+		return RichText{Text: text, Document: &doc}
+	}
+
+	return RichText{Text: text}
+}
+
+// Returns the plain text of the value
+func (r RichText) String() string {
+	if r.Document != nil {
+		return ToText(*r.Document)
+	}
+
+	return r.Text
+}
+
+func (r RichText) MarshalJSON() ([]byte, error) {
+	if r.Document != nil {
+		return json.Marshal(r.Document)
+	}
+
+	return json.Marshal(r.Text)
+}
+
+func (r *RichText) UnmarshalJSON(data []byte) error {
+	var doc Node
+
+	if err := json.Unmarshal(data, &r.Text); err == nil {
+		r.Document = nil
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	r.Document = &doc
+	r.Text = ToText(doc)
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package adf
+
+import "strings"
+
+// Extracts the plain text of an ADF node. Block elements are separated by new lines, list items are prefixed by a
+// dash and mentions are written back as [~accountid:ID].
+func ToText(n Node) string {
+	var sb strings.Builder
+	writeText(&sb, n)
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func writeText(sb *strings.Builder, n Node) {
+	switch n.Type {
+	case TypeText:
+		sb.WriteString(n.Text)
+	case TypeHardBreak:
+		sb.WriteString("\n")
+	case TypeMention:
+		if text := n.StringAttr("text"); text != "" {
+			sb.WriteString(text)
+		} else {
+			sb.WriteString("[~accountid:" + n.StringAttr("id") + "]")
+		}
+	case TypeListItem:
+		sb.WriteString("- ")
+		writeChildren(sb, n)
+	case TypeParagraph, TypeHeading, TypeCodeBlock:
+		writeChildren(sb, n)
+		sb.WriteString("\n")
+	default:
+		writeChildren(sb, n)
+	}
+}
+
+func writeChildren(sb *strings.Builder, n Node) {
+	for _, child := range n.Content {
+		writeText(sb, child)
+	}
+}
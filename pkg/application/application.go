@@ -6,10 +6,17 @@
 package application
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/chaining"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 type JiraAPIResourceInterace interface {
@@ -17,12 +24,14 @@ type JiraAPIResourceInterace interface {
 
 	initFlagsAndParameters() error
 	configurationReady() error
+	createClient() error
 	setupPipeline() error
 }
 
 // This struct represent a basic holder of the application parameters and context
 type JiraAPIResourceApp struct {
 	params   configuration.JiraAPIResourceParameters
+	client   *rest.Client
 	pipeline chaining.Pipeline
 }
 
@@ -38,11 +47,18 @@ func (app *JiraAPIResourceApp) Run() error {
 		return err
 	}
 
+	if err := app.createClient(); err != nil {
+		return err
+	}
+
 	if err := app.setupPipeline(); err != nil {
 		return err
 	}
 
-	return app.pipeline.Execute(&app.params)
+	ctx, cancel := app.runContext()
+	defer cancel()
+
+	return app.pipeline.Execute(ctx, &app.params)
 }
 
 func (app *JiraAPIResourceApp) initFlagsAndParameters() error {
@@ -69,10 +85,49 @@ func (app *JiraAPIResourceApp) configurationReady() error {
 	return nil
 }
 
+func (app *JiraAPIResourceApp) createClient() error {
+	client, err := rest.NewClient(app.params)
+	if err != nil {
+		return err
+	}
+
+	app.client = client
+	return nil
+}
+
+// Returns the context of the run, which expires after the 'timeout' parameter when specified. It is also cancelled
+// when the process receives SIGTERM or SIGINT (i.e. when Concourse aborts the build): the pipeline then stops taking
+// new issues, aborts the request in flight and closes back the issues it forced open.
+func (app *JiraAPIResourceApp) runContext() (context.Context, context.CancelFunc) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if d, err := helpers.ParseDuration(helpers.StringPtrValue(app.params.Timeout)); err == nil && d > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), d)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+
+	go func() {
+		select {
+		case s := <-signals:
+			log.Logger.Warningf("Received %v, aborting the run", s)
+			cancel()
+		case <-ctx.Done():
+		}
+
+		signal.Stop(signals)
+	}()
+
+	return ctx, cancel
+}
+
 func (app *JiraAPIResourceApp) setupPipeline() error {
 	chaining.InitServiceRegistry()
 
 	app.pipeline = chaining.Pipeline{}
-	chain := chaining.GetServicesChain(app.params.Context)
-	return app.pipeline.BuildPipelineFromChain(chain, &app.params)
+	chain := chaining.GetServicesChain(app.params)
+	return app.pipeline.BuildPipelineFromChain(app.client, chain, &app.params)
 }
@@ -1,4 +1,50 @@
 package auth
 
-var Username string
-var Password string
+import "net/http"
+
+// Supported authentication types
+const (
+	TypeBasic  = "basic"  // HTTP basic authentication with a username and a password (or API token)
+	TypeBearer = "bearer" // Personal access token sent as a bearer token (Jira Data Center 8.14+)
+	TypeOAuth1 = "oauth1" // OAuth 1.0a RSA-SHA1 signed requests through an application link
+	TypeOAuth2 = "oauth2" // OAuth 2.0 access token obtained from a refresh token or client credentials (Jira Cloud)
+)
+
+// An Authenticator sets the credentials of an identity on the requests sent to the Jira API. Each client owns its
+// authenticator so that several identities (or Jira instances) can be used in a single run.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// A RenewableAuthenticator holds credentials that can be renewed when Jira rejects them (HTTP 401) before their known
+// expiry, such as a revoked OAuth 2.0 access token.
+type RenewableAuthenticator interface {
+	Authenticator
+
+	// Discards the credentials set on the rejected request, the next call to Authenticate obtains new ones. Credentials
+	// already renewed since that request (i.e. by a concurrent request) are kept.
+	Invalidate(rejected *http.Request)
+}
+
+// HTTP basic authentication
+type Basic struct {
+	Username string
+	Password string
+}
+
+// Personal access token sent in the 'Authorization' header
+type Bearer struct {
+	Token string
+}
+
+// See Authenticator for details
+func (b Basic) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(b.Username, b.Password)
+	return nil
+}
+
+// See Authenticator for details
+func (b Bearer) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+b.Token)
+	return nil
+}
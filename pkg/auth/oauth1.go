@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const oauth1SignatureMethod = "RSA-SHA1"
+
+// The credentials of an OAuth 1.0a consumer registered through a Jira application link. Jira only supports the
+// RSA-SHA1 signature method: the requests are signed with the private key of the consumer. The token secret is
+// kept for completeness but is not part of an RSA-SHA1 signature.
+type OAuth1Credentials struct {
+	ConsumerKey string
+	PrivateKey  *rsa.PrivateKey
+	AccessToken string
+	TokenSecret string
+}
+
+// Parses a PEM encoded RSA private key, either in the PKCS#1 ('RSA PRIVATE KEY') or in the PKCS#8 ('PRIVATE KEY')
+// format
+func ParsePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block found in the private key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("the private key is not an RSA key")
+	}
+
+	return rsaKey, nil
+}
+
+// See Authenticator for details
+func (c OAuth1Credentials) Authenticate(req *http.Request) error {
+	return c.Sign(req)
+}
+
+// Signs the request with the OAuth 1.0a RSA-SHA1 method and sets the resulting 'Authorization' header. The query
+// parameters of the request URL are part of the signature.
+func (c OAuth1Credentials) Sign(req *http.Request) error {
+	if c.PrivateKey == nil {
+		return errors.New("no private key to sign the request with")
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	oauthParams := map[string]string{
+		"oauth_consumer_key":     c.ConsumerKey,
+		"oauth_nonce":            hex.EncodeToString(nonce),
+		"oauth_signature_method": oauth1SignatureMethod,
+		"oauth_timestamp":        strconv.FormatInt(time.Now().Unix(), 10),
+		"oauth_token":            c.AccessToken,
+		"oauth_version":          "1.0",
+	}
+
+	digest := sha1.Sum([]byte(OAuth1BaseString(req.Method, req.URL, oauthParams)))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, c.PrivateKey, crypto.SHA1, digest[:])
+	if err != nil {
+		return err
+	}
+	oauthParams["oauth_signature"] = base64.StdEncoding.EncodeToString(signature)
+
+	keys := make([]string, 0, len(oauthParams))
+	for k := range oauthParams {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	header := make([]string, 0, len(keys))
+	for _, k := range keys {
+		header = append(header, fmt.Sprintf(`%s="%s"`, PercentEncode(k), PercentEncode(oauthParams[k])))
+	}
+	req.Header.Set("Authorization", "OAuth "+strings.Join(header, ", "))
+
+	return nil
+}
+
+// Builds the signature base string of a request as defined by RFC 5849 section 3.4.1: the method, the base URL and
+// the normalized query and oauth parameters. The 'oauth_signature' parameter is never part of the base string.
+func OAuth1BaseString(method string, u *url.URL, oauthParams map[string]string) string {
+	type pair struct{ key, value string }
+	var pairs []pair
+
+	for k, values := range u.Query() {
+		for _, v := range values {
+			pairs = append(pairs, pair{PercentEncode(k), PercentEncode(v)})
+		}
+	}
+
+	for k, v := range oauthParams {
+		if k != "oauth_signature" {
+			pairs = append(pairs, pair{PercentEncode(k), PercentEncode(v)})
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].key == pairs[j].key {
+			return pairs[i].value < pairs[j].value
+		}
+		return pairs[i].key < pairs[j].key
+	})
+
+	normalized := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		normalized = append(normalized, p.key+"="+p.value)
+	}
+
+	return strings.ToUpper(method) + "&" + PercentEncode(baseURL(u)) + "&" + PercentEncode(strings.Join(normalized, "&"))
+}
+
+// Returns the scheme, host and path of the URL. The port is omitted when it is the default one of the scheme.
+func baseURL(u *url.URL) string {
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host = host + ":" + port
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	return scheme + "://" + host + path
+}
+
+// Percent-encodes a value as required by RFC 5849 section 3.6: every byte except the unreserved characters
+// (ALPHA, DIGIT, '-', '.', '_', '~') is encoded with uppercase hexadecimal digits.
+func PercentEncode(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			_, _ = fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
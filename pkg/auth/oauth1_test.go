@@ -0,0 +1,145 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Local stub of a Jira server verifying the OAuth 1.0a RSA-SHA1 signature of every request it receives. The base
+// string is rebuilt independently of the signing code.
+func newVerifyingServer(t *testing.T, pub *rsa.PublicKey) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "OAuth ") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		oauthParams := map[string]string{}
+		for _, part := range strings.Split(strings.TrimPrefix(header, "OAuth "), ", ") {
+			kv := strings.SplitN(part, "=", 2)
+			v, _ := url.QueryUnescape(strings.Trim(kv[1], `"`))
+			oauthParams[kv[0]] = v
+		}
+
+		var params []string
+		for k, values := range r.URL.Query() {
+			for _, v := range values {
+				params = append(params, encode(k)+"="+encode(v))
+			}
+		}
+		for k, v := range oauthParams {
+			if k != "oauth_signature" {
+				params = append(params, encode(k)+"="+encode(v))
+			}
+		}
+		sort.Strings(params)
+
+		base := r.Method + "&" + encode("http://"+r.Host+r.URL.EscapedPath()) + "&" + encode(strings.Join(params, "&"))
+		digest := sha1.Sum([]byte(base))
+		signature, _ := base64.StdEncoding.DecodeString(oauthParams["oauth_signature"])
+
+		if oauthParams["oauth_signature_method"] != "RSA-SHA1" || oauthParams["oauth_consumer_key"] != "jira-api-resource" ||
+			oauthParams["oauth_token"] != "access-token" || rsa.VerifyPKCS1v15(pub, crypto.SHA1, digest[:], signature) != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func encode(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
+func newCredentials(t *testing.T) (auth.OAuth1Credentials, *rsa.PublicKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "problem generating the RSA key")
+
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	parsed, err := auth.ParsePrivateKey(string(pemKey))
+	require.NoError(t, err, "problem parsing the PEM private key")
+
+	return auth.OAuth1Credentials{
+		ConsumerKey: "jira-api-resource",
+		PrivateKey:  parsed,
+		AccessToken: "access-token",
+		TokenSecret: "token-secret",
+	}, &key.PublicKey
+}
+
+func TestOAuth1Credentials_Sign(t *testing.T) {
+	credentials, pub := newCredentials(t)
+	server := newVerifyingServer(t, pub)
+	defer server.Close()
+
+	t.Run("signed request with query parameters is accepted", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/rest/api/2/issue/ABC-123?expand=names&fields=summary,status", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, credentials.Sign(req))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("signed request with reserved characters in the query is accepted", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/rest/api/2/search?jql="+url.QueryEscape("project = ABC AND status ~ \"In Progress\""), nil)
+		require.NoError(t, err)
+
+		require.NoError(t, credentials.Sign(req))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("request whose query was changed after signing is rejected", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/rest/api/2/issue/ABC-123?expand=names", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, credentials.Sign(req))
+		req.URL.RawQuery = "expand=changelog"
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("request signed with another key is rejected", func(t *testing.T) {
+		other, _ := newCredentials(t)
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/rest/api/2/issue/ABC-123", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, other.Sign(req))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestPercentEncode(t *testing.T) {
+	assert.Equal(t, "project%20%3D%20ABC~-._", auth.PercentEncode("project = ABC~-._"))
+	assert.Equal(t, "%C3%A9t%C3%A9%2A", auth.PercentEncode("été*"))
+}
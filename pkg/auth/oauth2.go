@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultOAuth2TokenURL   = "https://auth.atlassian.com/oauth/token"
+	DefaultOAuth2GatewayURL = "https://api.atlassian.com"
+
+	accessibleResourcesPath = "/oauth/token/accessible-resources"
+
+	// A token is refreshed slightly before it expires so that it doesn't expire while a request is in flight
+	oauth2ExpiryMargin = 30 * time.Second
+)
+
+// The credentials of an OAuth 2.0 integration on Jira Cloud. When a refresh token is specified the '3LO' refresh token
+// grant is used, otherwise the client credentials grant of a service account is used. The access token is obtained
+// lazily and refreshed whenever it is about to expire.
+type OAuth2Credentials struct {
+	ClientId     string
+	ClientSecret string
+	RefreshToken string
+	TokenURL     string
+	GatewayURL   string
+
+	// The http client used to reach the token and accessible-resources endpoints (http.DefaultClient when nil)
+	HTTPClient *http.Client
+
+	mutex       sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+// A site that the access token gives access to, as returned by the accessible-resources endpoint
+type AccessibleResource struct {
+	Id   string `json:"id"`
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+type oauth2TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+}
+
+// See Authenticator for details
+func (c *OAuth2Credentials) Authenticate(req *http.Request) error {
+	token, err := c.AccessToken(req.Context())
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// See RenewableAuthenticator for details
+func (c *OAuth2Credentials) Invalidate(rejected *http.Request) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.accessToken != "" && rejected.Header.Get("Authorization") == "Bearer "+c.accessToken {
+		log.Logger.Warning("The OAuth 2.0 access token was rejected before its expiry, a new one will be requested")
+		c.accessToken = ""
+	}
+}
+
+// Returns a valid access token, exchanging the refresh token or the client credentials for a new one when there is no
+// token yet or when the current one is about to expire
+func (c *OAuth2Credentials) AccessToken(ctx context.Context) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.accessToken != "" && time.Now().Add(oauth2ExpiryMargin).Before(c.expiry) {
+		return c.accessToken, nil
+	}
+
+	form := url.Values{}
+	form.Set("client_id", c.ClientId)
+	form.Set("client_secret", c.ClientSecret)
+	if c.RefreshToken != "" {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", c.RefreshToken)
+	} else {
+		form.Set("grant_type", "client_credentials")
+	}
+
+	log.Logger.Infof("Requesting OAuth 2.0 access token (%s grant)", form.Get("grant_type"))
+	req, err := http.NewRequest(http.MethodPost, c.tokenURL(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient().Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer helpers.DrainAndClose(resp.Body)
+
+	token := oauth2TokenResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("unable to read the OAuth 2.0 token response (HTTP %s): %v", resp.Status, err)
+	}
+
+	if token.Error == "invalid_grant" && c.RefreshToken != "" {
+		return "", fmt.Errorf("the OAuth 2.0 refresh token was rejected (%s): Atlassian rotates refresh tokens each time "+
+			"they are exchanged, so the one of the source was most likely already used by a previous run or revoked. "+
+			"Authorize the integration again and update the 'refresh_token' of the source, or use the client credentials "+
+			"of a service account instead", token.Description)
+	}
+
+	if resp.StatusCode != http.StatusOK || token.AccessToken == "" {
+		return "", fmt.Errorf("unable to obtain an OAuth 2.0 access token (HTTP %s): %s %s", resp.Status, token.Error, token.Description)
+	}
+
+	c.accessToken = token.AccessToken
+	c.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	if token.RefreshToken != "" {
+		// Refresh tokens are rotated: the previous one can't be used anymore
+		c.RefreshToken = token.RefreshToken
+	}
+
+	return c.accessToken, nil
+}
+
+// Returns the id of the cloud site targeted by 'siteURL' among the sites the access token gives access to. When the
+// token only gives access to a single site, that site is used whatever its URL.
+func (c *OAuth2Credentials) DiscoverCloudId(ctx context.Context, siteURL string) (string, error) {
+	token, err := c.AccessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(c.gatewayURL(), "/")+accessibleResourcesPath, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer helpers.DrainAndClose(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to list the accessible resources (HTTP %s)", resp.Status)
+	}
+
+	var resources []AccessibleResource
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		return "", err
+	}
+
+	return selectCloudId(resources, siteURL)
+}
+
+func selectCloudId(resources []AccessibleResource, siteURL string) (string, error) {
+	if len(resources) == 0 {
+		return "", errors.New("the OAuth 2.0 access token doesn't give access to any site")
+	}
+
+	if site, err := url.Parse(siteURL); err == nil && site.Host != "" {
+		for _, r := range resources {
+			if u, err := url.Parse(r.URL); err == nil && strings.EqualFold(u.Host, site.Host) {
+				return r.Id, nil
+			}
+		}
+	}
+
+	if len(resources) == 1 {
+		return resources[0].Id, nil
+	}
+
+	return "", fmt.Errorf("none of the %d sites accessible with the OAuth 2.0 access token matches %s", len(resources), siteURL)
+}
+
+func (c *OAuth2Credentials) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+
+	return c.HTTPClient
+}
+
+func (c *OAuth2Credentials) tokenURL() string {
+	if c.TokenURL == "" {
+		return DefaultOAuth2TokenURL
+	}
+
+	return c.TokenURL
+}
+
+func (c *OAuth2Credentials) gatewayURL() string {
+	if c.GatewayURL == "" {
+		return DefaultOAuth2GatewayURL
+	}
+
+	return c.GatewayURL
+}
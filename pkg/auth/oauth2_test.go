@@ -0,0 +1,147 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Local fake of the Atlassian token and accessible-resources endpoints. Every issued token expires after 'expiresIn'
+// seconds and refresh tokens are rotated.
+type fakeAuthServer struct {
+	*httptest.Server
+	expiresIn int
+	issued    int
+	grants    []string
+}
+
+func newFakeAuthServer(expiresIn int) *fakeAuthServer {
+	f := &fakeAuthServer{expiresIn: expiresIn}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("client_id") != "client" || r.Form.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"access_denied","error_description":"Unauthorized"}`))
+			return
+		}
+
+		grant := r.Form.Get("grant_type")
+		if grant == "refresh_token" && r.Form.Get("refresh_token") != fmt.Sprintf("refresh-%d", f.issued) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"Unknown or invalid refresh token."}`))
+			return
+		}
+
+		f.issued++
+		f.grants = append(f.grants, grant)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  fmt.Sprintf("token-%d", f.issued),
+			"refresh_token": fmt.Sprintf("refresh-%d", f.issued),
+			"expires_in":    f.expiresIn,
+		})
+	})
+
+	mux.HandleFunc("/oauth/token/accessible-resources", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", f.issued) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(`[
+			{"id": "11111111-aaaa", "url": "https://first.atlassian.net", "name": "first"},
+			{"id": "22222222-bbbb", "url": "https://second.atlassian.net", "name": "second"}
+		]`))
+	})
+
+	f.Server = httptest.NewServer(mux)
+	return f
+}
+
+func TestOAuth2Credentials_AccessToken(t *testing.T) {
+	t.Run("client credentials are exchanged once for a long lived token", func(t *testing.T) {
+		server := newFakeAuthServer(3600)
+		defer server.Close()
+		c := &auth.OAuth2Credentials{ClientId: "client", ClientSecret: "secret", TokenURL: server.URL + "/oauth/token"}
+
+		first, err := c.AccessToken(context.Background())
+		require.NoError(t, err)
+		second, err := c.AccessToken(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, "token-1", first)
+		assert.Equal(t, "token-1", second)
+		assert.Equal(t, []string{"client_credentials"}, server.grants)
+	})
+
+	t.Run("expired token is refreshed with the rotated refresh token", func(t *testing.T) {
+		server := newFakeAuthServer(1)
+		defer server.Close()
+		c := &auth.OAuth2Credentials{ClientId: "client", ClientSecret: "secret", RefreshToken: "refresh-0", TokenURL: server.URL + "/oauth/token"}
+
+		first, err := c.AccessToken(context.Background())
+		require.NoError(t, err)
+		second, err := c.AccessToken(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, "token-1", first)
+		assert.Equal(t, "token-2", second)
+		assert.Equal(t, "refresh-2", c.RefreshToken)
+		assert.Equal(t, []string{"refresh_token", "refresh_token"}, server.grants)
+	})
+
+	t.Run("refresh token already rotated by a previous run returns an error naming the rotation", func(t *testing.T) {
+		server := newFakeAuthServer(3600)
+		defer server.Close()
+		c := &auth.OAuth2Credentials{ClientId: "client", ClientSecret: "secret", RefreshToken: "refresh-0", TokenURL: server.URL + "/oauth/token"}
+		_, err := c.AccessToken(context.Background())
+		require.NoError(t, err)
+
+		// The next run starts again from the refresh token of the source
+		next := &auth.OAuth2Credentials{ClientId: "client", ClientSecret: "secret", RefreshToken: "refresh-0", TokenURL: server.URL + "/oauth/token"}
+		_, err = next.AccessToken(context.Background())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "refresh token was rejected")
+		assert.Contains(t, err.Error(), "rotates refresh tokens")
+	})
+
+	t.Run("rejected credentials return an error", func(t *testing.T) {
+		server := newFakeAuthServer(3600)
+		defer server.Close()
+		c := &auth.OAuth2Credentials{ClientId: "client", ClientSecret: "wrong", TokenURL: server.URL + "/oauth/token"}
+
+		_, err := c.AccessToken(context.Background())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestOAuth2Credentials_DiscoverCloudId(t *testing.T) {
+	server := newFakeAuthServer(3600)
+	defer server.Close()
+
+	t.Run("cloud id of the site matching the URL", func(t *testing.T) {
+		c := &auth.OAuth2Credentials{ClientId: "client", ClientSecret: "secret", TokenURL: server.URL + "/oauth/token", GatewayURL: server.URL}
+
+		id, err := c.DiscoverCloudId(context.Background(), "https://second.atlassian.net/rest/api/3")
+
+		require.NoError(t, err)
+		assert.Equal(t, "22222222-bbbb", id)
+	})
+
+	t.Run("no site matching the URL", func(t *testing.T) {
+		c := &auth.OAuth2Credentials{ClientId: "client", ClientSecret: "secret", TokenURL: server.URL + "/oauth/token", GatewayURL: server.URL}
+
+		_, err := c.DiscoverCloudId(context.Background(), "https://third.atlassian.net/rest/api/3")
+
+		assert.Error(t, err)
+	})
+}
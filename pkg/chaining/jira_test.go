@@ -0,0 +1,243 @@
+package chaining_test
+
+import (
+	"context"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/chaining"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/jiratest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Returns the parameters editing the 'Release Notes' custom field of the issues
+func newEditParams(value, fieldType string, issues ...string) configuration.JiraAPIResourceParameters {
+	params := newPipelineParams(1, false, issues...)
+	params.Context = configuration.EditCustomField
+
+	destination, name := "", "Release Notes"
+	params.Destination = &destination
+	params.EditCustomFieldParam.CustomFieldName = &name
+	params.EditCustomFieldParam.CustomFieldType = &fieldType
+	params.EditCustomFieldParam.CustomFieldValue = &value
+
+	return params
+}
+
+// Executes the services chain of the context of the parameters against the fake Jira
+func executeContext(server *jiratest.Server, params configuration.JiraAPIResourceParameters, retry rest.RetryPolicy) error {
+	client := &rest.Client{BaseURL: server.APIURL(), HTTPClient: server.Client(), Retry: retry, Cache: rest.NewIssueCache()}
+
+	chaining.InitServiceRegistry()
+	pipeline := chaining.Pipeline{}
+	_ = pipeline.BuildPipelineFromChain(client, chaining.GetServicesChain(params), &params)
+	return pipeline.Execute(context.Background(), &params)
+}
+
+// Returns the requests modifying Jira, as "METHOD path body"
+func mutations(server *jiratest.Server) []string {
+	var requests []string
+	for _, r := range server.Requests() {
+		if r.Method != http.MethodGet {
+			requests = append(requests, r.Method+" "+r.Path+" "+string(r.Body))
+		}
+	}
+
+	return requests
+}
+
+func TestEditCustomField(t *testing.T) {
+	t.Run("the custom field is looked up by name and set", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+
+		id := server.AddCustomField("Release Notes")
+		server.AddIssue(jiratest.Issue{Key: "ABC-1"})
+		server.AddIssue(jiratest.Issue{Key: "ABC-2"})
+
+		require.NoError(t, executeContext(server, newEditParams("v1.2.3", "string", "ABC-1", "ABC-2"), rest.RetryPolicy{}))
+
+		assert.Equal(t, []string{
+			`PUT /issue/ABC-1 {"fields":{"` + id + `":"v1.2.3"}}`,
+			`PUT /issue/ABC-2 {"fields":{"` + id + `":"v1.2.3"}}`,
+		}, mutations(server))
+
+		issue, _ := server.Issue("ABC-2")
+		assert.Equal(t, "v1.2.3", issue.Fields[id])
+	})
+
+	t.Run("a numeric value is sent as a number unless the field is a string", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+
+		id := server.AddCustomField("Release Notes")
+		server.AddIssue(jiratest.Issue{Key: "ABC-1"})
+
+		require.NoError(t, executeContext(server, newEditParams("42", "number", "ABC-1"), rest.RetryPolicy{}))
+		assert.Equal(t, []string{`PUT /issue/ABC-1 {"fields":{"` + id + `":42}}`}, mutations(server))
+	})
+
+	t.Run("nothing is edited when the custom field doesn't exist", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+
+		server.AddIssue(jiratest.Issue{Key: "ABC-1"})
+
+		assert.Error(t, executeContext(server, newEditParams("v1.2.3", "string", "ABC-1"), rest.RetryPolicy{}))
+		assert.Empty(t, mutations(server))
+	})
+
+	t.Run("the edit is sent again after a server error", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+
+		id := server.AddCustomField("Release Notes")
+		server.AddIssue(jiratest.Issue{Key: "ABC-1"})
+		server.InjectFault(jiratest.Fault{Method: http.MethodPut, Path: "/issue/ABC-1", Status: http.StatusServiceUnavailable, Times: 1})
+
+		retry := rest.RetryPolicy{Retries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+		require.NoError(t, executeContext(server, newEditParams("v1.2.3", "string", "ABC-1"), retry))
+
+		assert.Len(t, server.RequestsTo(http.MethodPut, "/issue/ABC-1"), 2)
+		issue, _ := server.Issue("ABC-1")
+		assert.Equal(t, "v1.2.3", issue.Fields[id])
+	})
+}
+
+func TestForceOpen(t *testing.T) {
+	t.Run("a closed issue is reopened, edited and closed back", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+
+		id := server.AddCustomField("Release Notes")
+		server.AddIssue(jiratest.Issue{Key: "ABC-1", Status: "Closed"})
+
+		params := newEditParams("v1.2.3", "string", "ABC-1")
+		*params.Flags.ForceOpen = true
+		require.NoError(t, executeContext(server, params, rest.RetryPolicy{}))
+
+		// The transitions of the default workflow are 'Reopen Issue' (3) and 'Close Issue' (2)
+		assert.Equal(t, []string{
+			`POST /issue/ABC-1/transitions {"transition":{"id":"3"}}`,
+			`PUT /issue/ABC-1 {"fields":{"` + id + `":"v1.2.3"}}`,
+			`POST /issue/ABC-1/transitions {"transition":{"id":"2"}}`,
+		}, mutations(server))
+
+		issue, _ := server.Issue("ABC-1")
+		assert.Equal(t, "Closed", issue.Status)
+		assert.Equal(t, "v1.2.3", issue.Fields[id])
+	})
+
+	t.Run("a closed issue is not edited without --forceOpen", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+
+		server.AddCustomField("Release Notes")
+		server.AddIssue(jiratest.Issue{Key: "ABC-1", Status: "Closed"})
+
+		assert.Error(t, executeContext(server, newEditParams("v1.2.3", "string", "ABC-1"), rest.RetryPolicy{}))
+		assert.Empty(t, mutations(server))
+	})
+
+	t.Run("the issue is closed back when the edit fails", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+
+		server.AddCustomField("Release Notes")
+		server.AddIssue(jiratest.Issue{Key: "ABC-1", Status: "Closed"})
+		server.InjectFault(jiratest.Fault{Method: http.MethodPut, Path: "/issue/ABC-1", Status: http.StatusBadRequest})
+
+		params := newEditParams("v1.2.3", "string", "ABC-1")
+		*params.Flags.ForceOpen = true
+		assert.Error(t, executeContext(server, params, rest.RetryPolicy{}))
+
+		issue, _ := server.Issue("ABC-1")
+		assert.Equal(t, "Closed", issue.Status)
+		assert.Len(t, server.RequestsTo(http.MethodPost, "/issue/ABC-1/transitions"), 2)
+	})
+}
+
+// Returns the parameters of the context posting (AddComment) or pruning (PruneComments) the comments of the upsert key
+func newCommentParams(context configuration.Context, body, upsertKey, apiVersion string, issues ...string) configuration.JiraAPIResourceParameters {
+	params := newPipelineParams(1, false, issues...)
+	params.Context = context
+	params.APIVersion = &apiVersion
+
+	match, olderThan, keepLast, action := "", "", 0, configuration.PruneEdit
+	params.AddComment.CommentBody = &body
+	params.AddComment.CommentUpsertKey = &upsertKey
+	params.PruneComments.Match = &match
+	params.PruneComments.OlderThan = &olderThan
+	params.PruneComments.KeepLast = &keepLast
+	params.PruneComments.Action = &action
+
+	return params
+}
+
+func TestPruneComments(t *testing.T) {
+	for _, apiVersion := range []string{configuration.APIVersion2, configuration.APIVersion3} {
+		t.Run("a comment edited by the prune is still found by a later upsert with the API version "+apiVersion, func(t *testing.T) {
+			// Arrange
+			server := jiratest.NewServer()
+			defer server.Close()
+			server.AddIssue(jiratest.Issue{Key: "ABC-1"})
+
+			upsert := newCommentParams(configuration.AddComment, "Deployed to QA", "deploy.qa", apiVersion, "ABC-1")
+			require.NoError(t, executeContext(server, upsert, rest.RetryPolicy{}))
+
+			// Act
+			prune := newCommentParams(configuration.PruneComments, "Outdated", "deploy.qa", apiVersion, "ABC-1")
+			require.NoError(t, executeContext(server, prune, rest.RetryPolicy{}))
+
+			upsert = newCommentParams(configuration.AddComment, "Deployed to QA again", "deploy.qa", apiVersion, "ABC-1")
+			require.NoError(t, executeContext(server, upsert, rest.RetryPolicy{}))
+
+			// Assert: the pruned comment was edited in place instead of posting a duplicate
+			issue, _ := server.Issue("ABC-1")
+			require.Len(t, issue.Comments, 1)
+			assert.Contains(t, string(issue.Comments[0].Body), "Deployed to QA again")
+			assert.Len(t, server.RequestsTo(http.MethodPost, "/issue/ABC-1/comment"), 1)
+			assert.Len(t, server.RequestsTo(http.MethodPut, "/issue/ABC-1/comment/"+issue.Comments[0].Id), 2)
+		})
+	}
+}
+
+func TestServiceDeskComments(t *testing.T) {
+	for _, apiVersion := range []string{configuration.APIVersion2, configuration.APIVersion3} {
+		t.Run("a public comment upserted through the service desk API bears no visible marker with the API version "+apiVersion, func(t *testing.T) {
+			// Arrange
+			server := jiratest.NewServer()
+			defer server.Close()
+			server.AddIssue(jiratest.Issue{Key: "ABC-1"})
+
+			newParams := func(context configuration.Context, body string) configuration.JiraAPIResourceParameters {
+				params := newCommentParams(context, body, "deploy.qa", apiVersion, "ABC-1")
+				serviceDesk := configuration.ServiceDeskPublic
+				params.AddComment.CommentServiceDesk = &serviceDesk
+				return params
+			}
+
+			// Act
+			require.NoError(t, executeContext(server, newParams(configuration.AddComment, "Deployed to QA"), rest.RetryPolicy{}))
+			require.NoError(t, executeContext(server, newParams(configuration.PruneComments, "Outdated"), rest.RetryPolicy{}))
+			require.NoError(t, executeContext(server, newParams(configuration.AddComment, "Deployed to QA again"), rest.RetryPolicy{}))
+
+			// Assert: the key is stored as a property of the comment, which is edited in place
+			issue, _ := server.Issue("ABC-1")
+			require.Len(t, issue.Comments, 1)
+			comment := issue.Comments[0]
+			assert.Contains(t, string(comment.Body), "Deployed to QA again")
+			assert.NotContains(t, string(comment.Body), "anchor")
+			require.Len(t, comment.Properties, 1)
+			assert.Equal(t, "jira-api-resource.upsert", comment.Properties[0].Key)
+			assert.JSONEq(t, `{"key":"deploy.qa"}`, string(comment.Properties[0].Value))
+
+			assert.Len(t, server.RequestsTo(http.MethodPost, "/rest/servicedeskapi/request/ABC-1/comment"), 1)
+			assert.Len(t, server.RequestsTo(http.MethodPut, "/comment/"+comment.Id+"/properties/jira-api-resource.upsert"), 1)
+			assert.Len(t, server.RequestsTo(http.MethodPut, "/issue/ABC-1/comment/"+comment.Id), 2)
+		})
+	}
+}
@@ -1,106 +1,351 @@
 package chaining
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/reading"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"strings"
+	"sync"
+	"time"
 )
 
+// Time given to the cleanup (closing back a forced open issue) once the context of the run is done
+const cleanupTimeout = 30 * time.Second
+
+// The Pipeline executes the services chain for every issue of the list. The issues are processed by a bounded pool of
+// workers, each issue with its own copy of the parameters, its own services and its own cross steps values.
 type Pipeline struct {
-	steps    []Step
-	length   int
-	csValues CrossStepsValues
+	chain       []ServiceFactory
+	client      *rest.Client
+	parallelism int
+
+	// Issues forced open by the run that were not closed back yet
+	mutex      sync.Mutex
+	forcedOpen []string
+
+	// Issues targeted by the workers with '--forceOpen', by key
+	targets map[string]*forcedTarget
 }
 
-func (p *Pipeline) BuildPipelineFromChain(chain []service.Service, params *configuration.JiraAPIResourceParameters) error {
-	for s := range chain {
-		// If it's the last element, then the lastStep flag is set to true to trigger the output
-		p.addStep(chain[s], params, s == len(chain)-1)
-	}
+// An issue targeted by one or more workers with '--forceOpen'. Several issues of the list share the same target when
+// they are children of the same parent with '--forceOnParent': the target is forced open by its first user and closed
+// back by its last one, so that the workers don't close it under each other.
+type forcedTarget struct {
+	// Serializes the forced opening and the closing back of the issue
+	mutex sync.Mutex
+
+	// Number of workers using the issue, guarded by the mutex of the pipeline
+	users int
+
+	// Whether the status of the issue was checked (and the issue forced open if needed) for the current users
+	ready  bool
+	opened bool
+}
+
+// The outcome of the execution of the pipeline for one issue
+type IssueResult struct {
+	Issue     string
+	Processed bool
+	Err       error
+}
 
-	p.length = len(p.steps)
+// Prepares the pipeline to execute the services chain. Every API call is performed with the client.
+func (p *Pipeline) BuildPipelineFromChain(client *rest.Client, chain []ServiceFactory, params *configuration.JiraAPIResourceParameters) error {
+	p.client = client
+	p.chain = chain
+	p.parallelism = 1
+
+	if params.Parallelism != nil && *params.Parallelism > 1 {
+		p.parallelism = *params.Parallelism
+	}
 
 	return nil
 }
 
-func (p *Pipeline) addStep(s service.Service, params *configuration.JiraAPIResourceParameters, lastStep bool) {
-	newStep := Step{}
-	newStep.Service = s
-	newStep.Name = s.Name()
-	newStep.params = params
-	newStep.Last = lastStep
+// Executes the pipeline for every issue. Once the context is done, or once an issue failed (unless '--keepGoing' was
+// specified), no new issue is processed and the API calls in progress are aborted. The errors are reported in the
+// order of the issue list.
+func (p *Pipeline) Execute(ctx context.Context, params *configuration.JiraAPIResourceParameters) error {
+	defer p.reportForcedOpen(params)
+
+	if p.client.DryRun != nil {
+		defer p.client.DryRun.LogSummary(params.IssueList)
+	}
+
+	if !params.Context.RequiresIssues() {
+		// The chain is executed once, not for each issue
+		return p.executeSteps(ctx, *params, CrossStepsValues{mapping: make(map[string]string)})
+	}
+
+	if helpers.IsBoolPtrTrue(params.Flags.Prefetch) {
+		p.prefetch(ctx, params)
+	}
+
+	results := p.executeAll(ctx, *params)
+
+	var failures []string
+	for _, r := range results {
+		if r.Err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", r.Issue, r.Err))
+		} else if !r.Processed {
+			failures = append(failures, fmt.Sprintf("%s: not processed", r.Issue))
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	if helpers.IsBoolPtrTrue(params.Flags.KeepGoingOnError) && ctx.Err() == nil {
+		log.Logger.Warningf("Error detected on %d issue(s) but '--keepGoing' was specified", len(failures))
+		for _, f := range failures {
+			log.Logger.Warning(f)
+		}
+		return nil
+	}
 
-	p.steps = append(p.steps, newStep)
+	return errors.New(strings.Join(failures, "\n"))
 }
 
-func (p *Pipeline) Execute(params *configuration.JiraAPIResourceParameters) error {
-	if params.Meta.MultipleIssue {
-		for _, i := range params.IssueList {
-			params.ActiveIssue = i
-			log.Logger.Debug("Executing pipeline for issue ", i)
-			err := p.singleExecution(params)
+// Processes the issues with the pool of workers and returns their results in the order of the issue list
+func (p *Pipeline) executeAll(ctx context.Context, params configuration.JiraAPIResourceParameters) []IssueResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]IssueResult, len(params.IssueList))
+	for i, issue := range params.IssueList {
+		results[i].Issue = issue
+	}
+
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < p.parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
 
-			if err != nil {
-				return err
+			for i := range jobs {
+				err := p.executeIssue(ctx, params, params.IssueList[i])
+				results[i] = IssueResult{Issue: params.IssueList[i], Processed: true, Err: err}
+
+				if err != nil && !helpers.IsBoolPtrTrue(params.Flags.KeepGoingOnError) {
+					cancel()
+				}
 			}
+		}()
+	}
+
+feed:
+	for i := range params.IssueList {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
 		}
 	}
+	close(jobs)
+	wg.Wait()
 
-	params.ActiveIssue = params.IssueList[0]
-	log.Logger.Debug("Executing pipeline for issue ", params.ActiveIssue)
-	return p.singleExecution(params)
+	return results
 }
 
-func (p *Pipeline) singleExecution(params *configuration.JiraAPIResourceParameters) error {
-	forcedOpen := false
+// Executes the services chain for one issue. The parameters are a copy owned by this execution.
+func (p *Pipeline) executeIssue(ctx context.Context, params configuration.JiraAPIResourceParameters, issue string) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	params.ActiveIssue = issue
+	log.Logger.Debug("Executing pipeline for issue ", issue)
 
-	if err := p.loadIssueData(params); err != nil {
+	csValues := CrossStepsValues{mapping: make(map[string]string)}
+	if err := p.loadIssueData(ctx, &params); err != nil {
 		return err
 	}
 
-	if p.csValues.mapping[helpers.IssueForceOpenKey] != "" {
-		if forcedOpen = PerformForceOpen(params); forcedOpen {
-			p.csValues.mapping[helpers.IssueForceOpenKey] = ""
+	if helpers.IsBoolPtrTrue(params.Flags.ForceOpen) {
+		target := p.acquireTarget(params.ActiveIssue)
+
+		// The issue is closed back whatever happens to the steps, even when the context of the run is done
+		defer func() {
+			if closeErr := p.releaseTarget(target, &params); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}()
+
+		if err := p.forceOpen(ctx, target, &params); err != nil {
+			return err
 		}
 	}
 
-	for index := range p.steps {
-		log.Logger.Debug("Executing step (", index+1, "/", p.length, ")", p.steps[index].Name)
-		err := p.steps[index].Execute(p.csValues, p.steps[index].Last)
+	return p.executeSteps(ctx, params, csValues)
+}
 
-		if err != nil {
-			if helpers.IsBoolPtrTrue(params.Flags.KeepGoingOnError) {
-				log.Logger.Warning("Error detected but '--keepGoing' was specified")
-				log.Logger.Warning(err.Error())
-				break
-			} else {
-				return err
-			}
+// Executes each step of the services chain with new instances of the services
+func (p *Pipeline) executeSteps(ctx context.Context, params configuration.JiraAPIResourceParameters, csValues CrossStepsValues) error {
+	steps := p.buildSteps(&params)
+	for index := range steps {
+		log.Logger.Debug("Executing step (", index+1, "/", len(steps), ")", steps[index].Name, " for issue ", params.ActiveIssue)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := steps[index].Execute(ctx, csValues, steps[index].Last); err != nil {
+			return err
 		}
 
-		if index < p.length-1 {
-			ns := &p.steps[index+1]
-			p.csValues = p.steps[index].PrepareNextStep(ns, p.csValues)
+		if index < len(steps)-1 {
+			csValues = steps[index].PrepareNextStep(&steps[index+1], csValues)
 		}
 	}
 
-	if forcedOpen {
-		return PerformClose(params)
+	return nil
+}
+
+// Creates new instances of the services of the chain, so that no state is shared between the issues
+func (p *Pipeline) buildSteps(params *configuration.JiraAPIResourceParameters) []Step {
+	steps := make([]Step, 0, len(p.chain))
+
+	for index, factory := range p.chain {
+		s := factory()
+		steps = append(steps, Step{
+			Service: s,
+			Name:    s.Name(),
+			// If it's the last element, then the lastStep flag is set to true to trigger the output
+			Last:   index == len(p.chain)-1,
+			params: params,
+			client: p.client,
+		})
+	}
+
+	return steps
+}
+
+// Registers the worker as a user of the issue
+func (p *Pipeline) acquireTarget(issue string) *forcedTarget {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.targets == nil {
+		p.targets = make(map[string]*forcedTarget)
+	}
+
+	target, ok := p.targets[issue]
+	if !ok {
+		target = &forcedTarget{}
+		p.targets[issue] = target
+	}
+	target.users++
+
+	return target
+}
+
+// Forces the active issue open if it is closed, unless another user of the issue already did. The status is read once
+// the other users are done with the issue, since one of them may have just closed it back.
+func (p *Pipeline) forceOpen(ctx context.Context, target *forcedTarget, params *configuration.JiraAPIResourceParameters) error {
+	target.mutex.Lock()
+	defer target.mutex.Unlock()
+
+	if target.ready {
+		return nil
+	}
+
+	statusName, err := fetchStatus(ctx, p.client, params)
+	if err != nil {
+		return err
+	}
+	target.ready = true
+
+	if statusName != *params.ClosedStatusName {
+		return nil
+	}
+
+	// When the run is interrupted while forcing the issue open, the transition may have been applied nonetheless
+	if PerformForceOpen(ctx, p.client, params) || ctx.Err() != nil {
+		target.opened = true
+		p.addForcedOpen(params.ActiveIssue)
 	}
 
 	return nil
 }
 
-func (p *Pipeline) loadIssueData(params *configuration.JiraAPIResourceParameters) error {
+// Unregisters the worker as a user of the issue and closes it back if it was forced open and the worker is its last
+// user
+func (p *Pipeline) releaseTarget(target *forcedTarget, params *configuration.JiraAPIResourceParameters) error {
+	target.mutex.Lock()
+	defer target.mutex.Unlock()
+
+	p.mutex.Lock()
+	target.users--
+	last := target.users == 0
+	p.mutex.Unlock()
+
+	if !last {
+		return nil
+	}
+
+	// The next user of the issue checks its status again
+	target.ready = false
+	if !target.opened {
+		return nil
+	}
+	target.opened = false
+
+	return p.restoreClosed(params)
+}
+
+// Closes back the active issue, which was forced open, with a fresh context so that it is done even when the run was
+// interrupted
+func (p *Pipeline) restoreClosed(params *configuration.JiraAPIResourceParameters) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	if err := RestoreClosed(ctx, p.client, params); err != nil {
+		log.Logger.Errorf("Unable to close back issue %s: %v", params.ActiveIssue, err)
+		return err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for i, issue := range p.forcedOpen {
+		if issue == params.ActiveIssue {
+			p.forcedOpen = append(p.forcedOpen[:i], p.forcedOpen[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (p *Pipeline) addForcedOpen(issue string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.forcedOpen = append(p.forcedOpen, issue)
+}
+
+// Logs every issue the run forced open and could not close back, they have to be closed manually
+func (p *Pipeline) reportForcedOpen(params *configuration.JiraAPIResourceParameters) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, issue := range p.forcedOpen {
+		log.Logger.Errorf("Issue %s was forced open by this run and could not be restored to the '%s' status", issue, *params.ClosedStatusName)
+	}
+}
+
+func (p *Pipeline) loadIssueData(ctx context.Context, params *configuration.JiraAPIResourceParameters) error {
 	srvFetchData := &reading.ServiceFetchIssueData{}
-	values := CrossStepsValues{}
-	values.mapping = make(map[string]string, 0)
 
-	if err := service.Execute(srvFetchData, *params, false); err != nil {
+	if err := service.Execute(ctx, p.client, srvFetchData, *params, false); err != nil {
 		return err
 	}
 
@@ -113,16 +358,8 @@ func (p *Pipeline) loadIssueData(params *configuration.JiraAPIResourceParameters
 		}
 	}
 
-	if results[helpers.StatusNameKey] == *params.ClosedStatusName {
-		if helpers.IsBoolPtrTrue(params.Flags.ForceOpen) {
-			values.mapping[helpers.IssueForceOpenKey] = *params.TransitionName
-		} else {
-			return errors.New(fmt.Sprintf("issue %s is in the '%s' status and the '--forceOpen' flag was not specified", params.ActiveIssue, *params.ClosedStatusName))
-		}
-	}
-
-	if len(values.mapping) > 0 {
-		p.csValues.mapping = helpers.CopyMapString(values.mapping, p.csValues.mapping, true)
+	if results[helpers.StatusNameKey] == *params.ClosedStatusName && !helpers.IsBoolPtrTrue(params.Flags.ForceOpen) {
+		return errors.New(fmt.Sprintf("issue %s is in the '%s' status and the '--forceOpen' flag was not specified", params.ActiveIssue, *params.ClosedStatusName))
 	}
 
 	return nil
@@ -0,0 +1,159 @@
+package chaining
+
+import (
+	"context"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/jiratest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPipeline_Cancellation(t *testing.T) {
+	t.Run("an issue forced open is closed back when the run is cancelled during a step", func(t *testing.T) {
+		// Arrange
+		server := jiratest.NewServer()
+		defer server.Close()
+		server.AddCustomField("Release Notes")
+		server.AddIssue(jiratest.Issue{Key: "ABC-1", Status: "Closed"})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// The run is cancelled (i.e. SIGTERM) while the issue, already forced open, is being edited
+		server.OnRequest(func(w http.ResponseWriter, r *http.Request) bool {
+			if r.Method != http.MethodPut || !strings.HasSuffix(r.URL.Path, "/issue/ABC-1") {
+				return false
+			}
+
+			cancel()
+			<-r.Context().Done()
+			return true
+		})
+
+		params := newForceOpenParams(1, false, "ABC-1")
+
+		client := &rest.Client{BaseURL: server.APIURL(), HTTPClient: server.Client(), Cache: rest.NewIssueCache()}
+		InitServiceRegistry()
+		pipeline := Pipeline{}
+		require.NoError(t, pipeline.BuildPipelineFromChain(client, GetServicesChain(params), &params))
+
+		// Act
+		err := pipeline.Execute(ctx, &params)
+
+		// Assert: the issue was reopened, the edit aborted and the issue closed back with the 'Close Issue' transition
+		assert.Error(t, err)
+		transitions := server.RequestsTo(http.MethodPost, "/issue/ABC-1/transitions")
+		require.Len(t, transitions, 2)
+		assert.JSONEq(t, `{"transition":{"id":"3"}}`, string(transitions[0].Body))
+		assert.JSONEq(t, `{"transition":{"id":"2"}}`, string(transitions[1].Body))
+
+		issue, _ := server.Issue("ABC-1")
+		assert.Equal(t, "Closed", issue.Status)
+		assert.Empty(t, pipeline.forcedOpen)
+	})
+}
+
+func TestPipeline_ForceOnParent(t *testing.T) {
+	t.Run("the parent shared by concurrent children is forced open once and closed back after the last one", func(t *testing.T) {
+		// Arrange
+		server := jiratest.NewServer()
+		defer server.Close()
+		server.AddCustomField("Release Notes")
+		server.AddIssue(jiratest.Issue{Key: "ABC-1", Status: "Closed"})
+		server.AddIssue(jiratest.Issue{Key: "ABC-2", Status: "Closed", Parent: "ABC-1"})
+		server.AddIssue(jiratest.Issue{Key: "ABC-3", Status: "Closed", Parent: "ABC-1"})
+
+		// ABC-3 is loaded once the parent was forced open for ABC-2, whose edit completes once ABC-3 is editing the
+		// parent too. The edit for ABC-3 is held until the parent is closed back (or for a delay, when it isn't closed
+		// back under ABC-3).
+		var first, closed sync.Once
+		var mutex sync.Mutex
+		var statuses []string
+		edited, editing, closing := make(chan struct{}), make(chan struct{}), make(chan struct{})
+		server.OnRequest(func(w http.ResponseWriter, r *http.Request) bool {
+			switch {
+			case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/issue/ABC-3"):
+				wait(edited, 5*time.Second)
+			case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/issue/ABC-1"):
+				isFirst := false
+				first.Do(func() {
+					isFirst = true
+					close(edited)
+				})
+
+				if isFirst {
+					wait(editing, 5*time.Second)
+				} else {
+					close(editing)
+					wait(closing, 500*time.Millisecond)
+				}
+
+				issue, _ := server.Issue("ABC-1")
+				mutex.Lock()
+				statuses = append(statuses, issue.Status)
+				mutex.Unlock()
+			case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/issue/ABC-1/transitions"):
+				if issue, _ := server.Issue("ABC-1"); issue.Status == "Reopened" {
+					closed.Do(func() { close(closing) })
+				}
+			}
+			return false
+		})
+
+		params := newForceOpenParams(2, true, "ABC-2", "ABC-3")
+		client := &rest.Client{BaseURL: server.APIURL(), HTTPClient: server.Client(), Cache: rest.NewIssueCache()}
+		InitServiceRegistry()
+		pipeline := Pipeline{}
+		require.NoError(t, pipeline.BuildPipelineFromChain(client, GetServicesChain(params), &params))
+
+		// Act
+		err := pipeline.Execute(context.Background(), &params)
+
+		// Assert: both edits were applied to the reopened parent, which was closed back once
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Reopened", "Reopened"}, statuses)
+
+		transitions := server.RequestsTo(http.MethodPost, "/issue/ABC-1/transitions")
+		require.Len(t, transitions, 2)
+		assert.JSONEq(t, `{"transition":{"id":"3"}}`, string(transitions[0].Body))
+		assert.JSONEq(t, `{"transition":{"id":"2"}}`, string(transitions[1].Body))
+
+		issue, _ := server.Issue("ABC-1")
+		assert.Equal(t, "Closed", issue.Status)
+		assert.Empty(t, pipeline.forcedOpen)
+	})
+}
+
+// Parameters editing a custom field of the issues with '--forceOpen'
+func newForceOpenParams(parallelism int, forceOnParent bool, issues ...string) configuration.JiraAPIResourceParameters {
+	closed, transition, name, fieldType, value, destination := "Closed", "Reopened", "Release Notes", "string", "v1.2.3", ""
+	forceOpen, keepGoing := true, false
+	return configuration.JiraAPIResourceParameters{
+		Context:          configuration.EditCustomField,
+		ClosedStatusName: &closed,
+		TransitionName:   &transition,
+		Destination:      &destination,
+		IssueList:        issues,
+		Parallelism:      &parallelism,
+		EditCustomFieldParam: configuration.JiraApiResourceParametersEditCustomField{
+			CustomFieldName:  &name,
+			CustomFieldType:  &fieldType,
+			CustomFieldValue: &value,
+		},
+		Flags: configuration.JiraAPIResourceFlags{ForceOpen: &forceOpen, ForceOnParent: &forceOnParent, KeepGoingOnError: &keepGoing},
+	}
+}
+
+// Waits until the channel is closed, at most for the delay
+func wait(done chan struct{}, delay time.Duration) {
+	select {
+	case <-done:
+	case <-time.After(delay):
+	}
+}
@@ -0,0 +1,221 @@
+package chaining_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/chaining"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/reading"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Serves every issue as 'Open', except the ones whose key starts with 'MISSING' which are not found. The issues are
+// also served by the search API (jql=key in (...)). It records the maximum number of requests served concurrently.
+type issuesServer struct {
+	*httptest.Server
+
+	mutex     sync.Mutex
+	inFlight  int
+	maxFlight int
+	requests  map[string]int
+}
+
+func newIssuesServer() *issuesServer {
+	s := &issuesServer{requests: make(map[string]int)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search" {
+			s.search(w, r)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/issue/")
+
+		s.mutex.Lock()
+		s.inFlight++
+		s.requests[key]++
+		if s.inFlight > s.maxFlight {
+			s.maxFlight = s.inFlight
+		}
+		s.mutex.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		s.mutex.Lock()
+		s.inFlight--
+		s.mutex.Unlock()
+
+		if strings.HasPrefix(key, "MISSING") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"key":%q,"fields":{"status":{"name":"Open"}}}`, key)
+	}))
+
+	return s
+}
+
+func (s *issuesServer) search(w http.ResponseWriter, r *http.Request) {
+	var body reading.SearchRequest
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	s.mutex.Lock()
+	s.requests["search"]++
+	s.mutex.Unlock()
+
+	var issues, warnings []string
+	keys := strings.TrimSuffix(strings.TrimPrefix(body.Jql, "key in ("), ")")
+	for _, key := range strings.Split(keys, ",") {
+		if strings.HasPrefix(key, "MISSING") {
+			warnings = append(warnings, fmt.Sprintf("%q", fmt.Sprintf("An issue with key '%s' does not exist for field 'key'.", key)))
+		} else {
+			issues = append(issues, fmt.Sprintf(`{"key":%q,"fields":{"status":{"name":"Open"}}}`, key))
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, `{"issues":[%s],"names":{"status":"Status"},"warningMessages":[%s]}`, strings.Join(issues, ","), strings.Join(warnings, ","))
+}
+
+func newPipelineParams(parallelism int, keepGoing bool, issues ...string) configuration.JiraAPIResourceParameters {
+	closed, transition := "Closed", "Reopened"
+	forceOpen, forceOnParent, prefetch := false, false, false
+	fieldName := ""
+
+	return configuration.JiraAPIResourceParameters{
+		ClosedStatusName: &closed,
+		TransitionName:   &transition,
+		Parallelism:      &parallelism,
+		IssueList:        issues,
+		EditCustomFieldParam: configuration.JiraApiResourceParametersEditCustomField{
+			CustomFieldName: &fieldName,
+		},
+		Flags: configuration.JiraAPIResourceFlags{
+			Prefetch:         &prefetch,
+			ForceOpen:        &forceOpen,
+			ForceOnParent:    &forceOnParent,
+			KeepGoingOnError: &keepGoing,
+		},
+	}
+}
+
+func executePipeline(server *issuesServer, params configuration.JiraAPIResourceParameters) error {
+	chain := []chaining.ServiceFactory{func() service.Service { return &reading.ServiceFetchIssueData{} }}
+	return executeChain(server, params, chain)
+}
+
+func executeChain(server *issuesServer, params configuration.JiraAPIResourceParameters, chain []chaining.ServiceFactory) error {
+	client := &rest.Client{BaseURL: server.URL, HTTPClient: server.Client(), Retry: rest.RetryPolicy{}}
+	if helpers.IsBoolPtrTrue(params.Flags.Prefetch) {
+		client.Cache = rest.NewIssueCache()
+	}
+
+	pipeline := chaining.Pipeline{}
+	_ = pipeline.BuildPipelineFromChain(client, chain, &params)
+	return pipeline.Execute(context.Background(), &params)
+}
+
+func TestPipeline_Execute(t *testing.T) {
+	t.Run("every issue is processed once with at most 'parallelism' concurrent issues", func(t *testing.T) {
+		server := newIssuesServer()
+		defer server.Close()
+
+		issues := []string{"ABC-1", "ABC-2", "ABC-3", "ABC-4", "ABC-5", "ABC-6", "ABC-7", "ABC-8"}
+		assert.NoError(t, executePipeline(server, newPipelineParams(3, false, issues...)))
+
+		for _, issue := range issues {
+			// Loading the issue data, then the step of the chain
+			assert.Equal(t, 2, server.requests[issue], issue)
+		}
+		assert.True(t, server.maxFlight <= 3)
+		assert.True(t, server.maxFlight > 1)
+	})
+
+	t.Run("errors are reported in the order of the issue list", func(t *testing.T) {
+		server := newIssuesServer()
+		defer server.Close()
+
+		err := executePipeline(server, newPipelineParams(4, false, "MISSING-1", "ABC-1", "MISSING-2", "ABC-2"))
+
+		if assert.Error(t, err) {
+			previous := -1
+			for _, issue := range []string{"MISSING-1", "MISSING-2"} {
+				index := strings.Index(err.Error(), issue+": ")
+				assert.True(t, index > previous, issue)
+				previous = index
+			}
+		}
+	})
+
+	t.Run("issues are not processed after an error without keepGoing", func(t *testing.T) {
+		server := newIssuesServer()
+		defer server.Close()
+
+		err := executePipeline(server, newPipelineParams(1, false, "MISSING-1", "ABC-1", "ABC-2"))
+
+		if assert.Error(t, err) {
+			assert.True(t, strings.HasPrefix(err.Error(), "MISSING-1: "))
+		}
+		assert.Contains(t, err.Error(), "ABC-1: not processed")
+		assert.Contains(t, err.Error(), "ABC-2: not processed")
+		assert.Equal(t, 0, server.requests["ABC-1"])
+	})
+
+	t.Run("keepGoing processes every issue", func(t *testing.T) {
+		server := newIssuesServer()
+		defer server.Close()
+
+		assert.NoError(t, executePipeline(server, newPipelineParams(2, true, "MISSING-1", "ABC-1", "ABC-2")))
+		assert.Equal(t, 2, server.requests["ABC-1"])
+		assert.Equal(t, 2, server.requests["ABC-2"])
+	})
+
+	t.Run("prefetched issues are not read one by one", func(t *testing.T) {
+		server := newIssuesServer()
+		defer server.Close()
+
+		var issues []string
+		for i := 1; i <= 60; i++ {
+			issues = append(issues, fmt.Sprintf("ABC-%d", i))
+		}
+		params := newPipelineParams(4, true, append(issues, "MISSING-1")...)
+		*params.Flags.Prefetch = true
+
+		assert.NoError(t, executePipeline(server, params))
+		// Two batches
+		assert.Equal(t, 2, server.requests["search"])
+		for _, issue := range issues {
+			assert.Equal(t, 0, server.requests[issue], issue)
+		}
+		assert.Equal(t, 1, server.requests["MISSING-1"])
+	})
+
+	t.Run("prefetched issues are not served to the services reading the whole issue", func(t *testing.T) {
+		server := newIssuesServer()
+		defer server.Close()
+
+		params := newPipelineParams(2, false, "ABC-1", "ABC-2")
+		*params.Flags.Prefetch = true
+		destination := ""
+		params.Destination = &destination
+
+		chain := []chaining.ServiceFactory{
+			func() service.Service { return &reading.ServiceFetchIssueData{} },
+			func() service.Service { return &reading.ServiceReadIssue{} },
+		}
+
+		assert.NoError(t, executeChain(server, params, chain))
+		assert.Equal(t, 1, server.requests["search"])
+		// The issue data is served by the prefetch, the whole issue is read
+		assert.Equal(t, 1, server.requests["ABC-1"])
+		assert.Equal(t, 1, server.requests["ABC-2"])
+	})
+}
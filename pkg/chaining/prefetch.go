@@ -0,0 +1,39 @@
+package chaining
+
+import (
+	"context"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/reading"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"strings"
+)
+
+// Reads the data of every issue of the list through the search API and stores them in the cache of the client, so that
+// loading the issue data doesn't need one request per issue. A failed prefetch isn't fatal: the issues are then read
+// one by one.
+func (p *Pipeline) prefetch(ctx context.Context, params *configuration.JiraAPIResourceParameters) {
+	if p.client.Cache == nil || len(params.IssueList) == 0 {
+		return
+	}
+
+	srvPrefetch := &reading.ServicePrefetchIssues{}
+	if err := service.Execute(ctx, p.client, srvPrefetch, *params, false); err != nil {
+		log.Logger.Warningf("Unable to prefetch the issues, they are read one by one: %v", err)
+		return
+	}
+
+	issues := srvPrefetch.Issues()
+	for key, body := range issues {
+		p.client.Cache.Put(reading.IssueFieldsURL(p.client.BaseURL, key, reading.IssueDataFields), body)
+	}
+	log.Logger.Infof("Prefetched %d of %d issue(s)", len(issues), len(params.IssueList))
+
+	for _, key := range srvPrefetch.Missing() {
+		log.Logger.Warningf("Issue %s does not exist or is not visible", key)
+	}
+
+	if oldKeys, newKeys := srvPrefetch.Moved(); len(oldKeys) > 0 {
+		log.Logger.Warningf("Issue(s) %s were moved and are now known as %s", strings.Join(oldKeys, ", "), strings.Join(newKeys, ", "))
+	}
+}
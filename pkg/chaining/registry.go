@@ -3,11 +3,16 @@ package chaining
 import (
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/commenting"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/creating"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/editing"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/history"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/noop"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/properties"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/reading"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/status"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/user"
 )
 
 const (
@@ -16,26 +21,46 @@ const (
 	ServiceEditCustomFieldName = "srv_edit_field"
 	ServiceGetTransitions      = "srv_get_transitions"
 	ServiceDoTransition        = "srv_do_transitions"
+	ServiceFindComment         = "srv_find_comment"
 	ServiceAddComment          = "srv_add_comment"
+	ServiceGetMyself           = "srv_get_myself"
+	ServiceReadComments        = "srv_read_comments"
+	ServicePruneComments       = "srv_prune_comments"
+	ServiceReadChangelog       = "srv_read_changelog"
+	ServiceSetIssueProperty    = "srv_set_issue_property"
+	ServiceReadIssueProperty   = "srv_read_issue_property"
+	ServiceBulkCreate          = "srv_bulk_create"
 	ServiceUnknownName         = "srv_unknown"
 )
 
-var serviceRegistry = make(map[string]service.Service)
+// A ServiceFactory creates a new instance of a service. Each issue gets its own instances of the services of the chain,
+// since the services hold the state of the API calls.
+type ServiceFactory func() service.Service
+
+var serviceRegistry = make(map[string]ServiceFactory)
 
 func InitServiceRegistry() {
-	serviceRegistry[ServiceFetchIssueData] = &reading.ServiceFetchIssueData{}
-	serviceRegistry[ServiceReadIssueName] = &reading.ServiceReadIssue{}
-	serviceRegistry[ServiceEditCustomFieldName] = &editing.ServiceEditCustomField{}
-	serviceRegistry[ServiceGetTransitions] = &status.ServiceGetTransitions{}
-	serviceRegistry[ServiceDoTransition] = &status.ServiceDoTransition{}
-	serviceRegistry[ServiceAddComment] = &commenting.ServiceAddComment{}
-	serviceRegistry[ServiceUnknownName] = &noop.ServiceUnknown{}
+	serviceRegistry[ServiceFetchIssueData] = func() service.Service { return &reading.ServiceFetchIssueData{} }
+	serviceRegistry[ServiceReadIssueName] = func() service.Service { return &reading.ServiceReadIssue{} }
+	serviceRegistry[ServiceEditCustomFieldName] = func() service.Service { return &editing.ServiceEditCustomField{} }
+	serviceRegistry[ServiceGetTransitions] = func() service.Service { return &status.ServiceGetTransitions{} }
+	serviceRegistry[ServiceDoTransition] = func() service.Service { return &status.ServiceDoTransition{} }
+	serviceRegistry[ServiceFindComment] = func() service.Service { return &commenting.ServiceFindComment{} }
+	serviceRegistry[ServiceAddComment] = func() service.Service { return &commenting.ServiceAddComment{} }
+	serviceRegistry[ServiceGetMyself] = func() service.Service { return &user.ServiceGetMyself{} }
+	serviceRegistry[ServiceReadComments] = func() service.Service { return &commenting.ServiceReadComments{} }
+	serviceRegistry[ServicePruneComments] = func() service.Service { return &commenting.ServicePruneComments{} }
+	serviceRegistry[ServiceReadChangelog] = func() service.Service { return &history.ServiceReadChangelog{} }
+	serviceRegistry[ServiceSetIssueProperty] = func() service.Service { return &properties.ServiceSetIssueProperty{} }
+	serviceRegistry[ServiceReadIssueProperty] = func() service.Service { return &properties.ServiceReadIssueProperty{} }
+	serviceRegistry[ServiceBulkCreate] = func() service.Service { return &creating.ServiceBulkCreate{} }
+	serviceRegistry[ServiceUnknownName] = func() service.Service { return &noop.ServiceUnknown{} }
 }
 
-func GetServicesChain(c configuration.Context) []service.Service {
-	chain := make([]service.Service, 0)
+func GetServicesChain(params configuration.JiraAPIResourceParameters) []ServiceFactory {
+	chain := make([]ServiceFactory, 0)
 
-	switch c {
+	switch params.Context {
 	case configuration.ReadIssue:
 		chain = append(chain, serviceRegistry[ServiceReadIssueName])
 	case configuration.ReadStatus:
@@ -44,11 +69,28 @@ func GetServicesChain(c configuration.Context) []service.Service {
 		chain = append(chain, serviceRegistry[ServiceReadIssueName])
 		chain = append(chain, serviceRegistry[ServiceEditCustomFieldName])
 	case configuration.AddComment:
+		if !helpers.IsStringPtrNilOrEmtpy(params.AddComment.CommentUpsertKey) {
+			chain = append(chain, serviceRegistry[ServiceFindComment])
+		}
 		chain = append(chain, serviceRegistry[ServiceAddComment])
+	case configuration.ReadComments:
+		chain = append(chain, serviceRegistry[ServiceReadComments])
+	case configuration.ReadChangelog:
+		chain = append(chain, serviceRegistry[ServiceReadChangelog])
+	case configuration.SetIssueProperty:
+		chain = append(chain, serviceRegistry[ServiceSetIssueProperty])
+	case configuration.ReadIssueProperty:
+		chain = append(chain, serviceRegistry[ServiceReadIssueProperty])
+	case configuration.PruneComments:
+		chain = append(chain, serviceRegistry[ServiceGetMyself])
+		chain = append(chain, serviceRegistry[ServiceReadComments])
+		chain = append(chain, serviceRegistry[ServicePruneComments])
+	case configuration.BulkCreate:
+		chain = append(chain, serviceRegistry[ServiceBulkCreate])
 	case configuration.Unknown:
 		fallthrough
 	default:
-		chain = make([]service.Service, 0)
+		chain = make([]ServiceFactory, 0)
 	}
 
 	return chain
@@ -1,7 +1,9 @@
 package chaining
 
 import (
+	"context"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
 )
 
@@ -11,10 +13,11 @@ type Step struct {
 	Last    bool
 
 	params *configuration.JiraAPIResourceParameters
+	client *rest.Client
 }
 
-func (s *Step) Execute(csValues CrossStepsValues, lastStep bool) error {
-	return service.Execute(s.Service, *s.params, lastStep)
+func (s *Step) Execute(ctx context.Context, csValues CrossStepsValues, lastStep bool) error {
+	return service.Execute(ctx, s.client, s.Service, *s.params, lastStep)
 }
 
 func (s *Step) PrepareNextStep(ns *Step, csValues CrossStepsValues) CrossStepsValues {
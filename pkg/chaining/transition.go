@@ -1,19 +1,25 @@
 package chaining
 
 import (
+	"context"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/reading"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/status"
 )
 
-func PerformForceOpen(params *configuration.JiraAPIResourceParameters) bool {
-	if err := fetchTransitionsIfMissing(params); err != nil {
+func PerformForceOpen(ctx context.Context, client *rest.Client, params *configuration.JiraAPIResourceParameters) bool {
+	transitions, err := fetchTransitions(ctx, client, params)
+	if err != nil {
 		return false
 	}
 
 	srvDoTransition := &status.ServiceDoTransition{}
-	if err := service.Execute(srvDoTransition, *params, false); err != nil {
+	srvDoTransition.SetTransitions(transitions)
+	if err := service.Execute(ctx, client, srvDoTransition, *params, false); err != nil {
 		return false
 	}
 
@@ -21,13 +27,17 @@ func PerformForceOpen(params *configuration.JiraAPIResourceParameters) bool {
 	return true
 }
 
-func PerformClose(params *configuration.JiraAPIResourceParameters) error {
-	if err := fetchTransitionsIfMissing(params); err != nil {
+func PerformClose(ctx context.Context, client *rest.Client, params *configuration.JiraAPIResourceParameters) error {
+	// The transitions are fetched again since the ones available depend on the current status of the issue
+	transitions, err := fetchTransitions(ctx, client, params)
+	if err != nil {
 		return err
 	}
+
 	srvDoTransition := &status.ServiceDoTransition{}
+	srvDoTransition.SetTransitions(transitions)
 	srvDoTransition.OverwriteTransitionName(*params.ClosedStatusName)
-	if err := service.Execute(srvDoTransition, *params, false); err != nil {
+	if err := service.Execute(ctx, client, srvDoTransition, *params, false); err != nil {
 		return err
 	}
 
@@ -35,13 +45,38 @@ func PerformClose(params *configuration.JiraAPIResourceParameters) error {
 	return nil
 }
 
-func fetchTransitionsIfMissing(params *configuration.JiraAPIResourceParameters) error {
-	if status.TransitionsSlice.Transitions == nil {
-		srvGetTransitions := &status.ServiceGetTransitions{}
-		if err := service.Execute(srvGetTransitions, *params, false); err != nil {
-			return err
-		}
+// Closes back an issue that was forced open, unless it is already in the closed status. This is used as a cleanup, so
+// it also handles an issue whose forced opening was interrupted and may or may not have been applied.
+func RestoreClosed(ctx context.Context, client *rest.Client, params *configuration.JiraAPIResourceParameters) error {
+	statusName, err := fetchStatus(ctx, client, params)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	if statusName == *params.ClosedStatusName {
+		log.Logger.Debugf("Issue %s is already in the '%s' status", params.ActiveIssue, *params.ClosedStatusName)
+		return nil
+	}
+
+	return PerformClose(ctx, client, params)
+}
+
+// Fetches the current status of the active issue
+func fetchStatus(ctx context.Context, client *rest.Client, params *configuration.JiraAPIResourceParameters) (string, error) {
+	srvFetchData := &reading.ServiceFetchIssueData{}
+	if err := service.Execute(ctx, client, srvFetchData, *params, false); err != nil {
+		return "", err
+	}
+
+	return srvFetchData.GetResults()[helpers.StatusNameKey], nil
+}
+
+// Fetches the transitions available for the active issue. They are never shared between issues.
+func fetchTransitions(ctx context.Context, client *rest.Client, params *configuration.JiraAPIResourceParameters) (status.Transitions, error) {
+	srvGetTransitions := &status.ServiceGetTransitions{}
+	if err := service.Execute(ctx, client, srvGetTransitions, *params, false); err != nil {
+		return status.Transitions{}, err
+	}
+
+	return srvGetTransitions.Transitions(), nil
 }
@@ -1,5 +1,48 @@
 package commenting
 
+import (
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/adf"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/user"
+)
+
 type Comment struct {
-	Body string `json:"body"`
+	Id         string           `json:"id,omitempty"`
+	Author     *user.User       `json:"author,omitempty"`
+	Body       adf.RichText     `json:"body"`
+	Created    string           `json:"created,omitempty"`
+	Updated    string           `json:"updated,omitempty"`
+	Visibility *Visibility      `json:"visibility,omitempty"`
+	Properties []EntityProperty `json:"properties,omitempty"`
+}
+
+// This struct restricts the visibility of a comment to the members of a project role or of a group.
+type Visibility struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// This struct is a key/value pair stored on a comment. Unlike the body, it is never displayed by Jira.
+type EntityProperty struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// This struct is a representation of a comment posted through the Jira Service Management API. Internal comments
+// (public set to false) are only visible to the agents of the service desk.
+type ServiceDeskComment struct {
+	Body   string `json:"body"`
+	Public bool   `json:"public"`
+}
+
+// The comment created through the Jira Service Management API, of which only the id is read
+type ServiceDeskCommentCreated struct {
+	Id string `json:"id"`
+}
+
+// This struct is a representation of a page of comments as returned by the /issue/{key}/comment endpoint.
+type Comments struct {
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+	Comments   []Comment `json:"comments"`
 }
@@ -0,0 +1,74 @@
+package commenting
+
+import (
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// CommentFilter holds the criteria used to select comments of an issue. Every criterion is optional.
+type CommentFilter struct {
+	Author        string         // Account id, name, key, display name or email address of the author
+	Match         *regexp.Regexp // Regular expression the body must match
+	UpsertKey     string         // Upsert key of the marker the comment must bear (either it or Match is required)
+	CreatedAfter  time.Time      // Only comments created after this date are selected
+	CreatedBefore time.Time      // Only comments created before this date are selected
+	KeepLast      int            // Number of most recent comments (among the matching ones) that are never selected
+}
+
+// Returns true if the comment matches the author, regular expression and upsert marker criteria. The dates and the
+// number of comments to keep are only taken into account once every comment was read (see Select).
+func (f *CommentFilter) Matches(c Comment) bool {
+	if f.Author != "" && (c.Author == nil || !c.Author.Is(f.Author)) {
+		return false
+	}
+
+	if f.Match == nil && f.UpsertKey == "" {
+		return true
+	}
+
+	return (f.Match != nil && f.Match.MatchString(c.Body.String())) || HasUpsertMarker(c, f.UpsertKey)
+}
+
+// Sorts the matching comments from the oldest to the most recent one, leaves the last 'KeepLast' ones out and then
+// applies the dates criteria.
+func (f *CommentFilter) Select(comments []Comment) []Comment {
+	sorted := make([]Comment, len(comments))
+	copy(sorted, comments)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		first, _ := createdAt(sorted[i])
+		second, _ := createdAt(sorted[j])
+		return first.Before(second)
+	})
+
+	if f.KeepLast > 0 {
+		if f.KeepLast >= len(sorted) {
+			return make([]Comment, 0)
+		}
+		sorted = sorted[:len(sorted)-f.KeepLast]
+	}
+
+	selected := make([]Comment, 0, len(sorted))
+	for _, c := range sorted {
+		created, ok := createdAt(c)
+		if !ok && (!f.CreatedAfter.IsZero() || !f.CreatedBefore.IsZero()) {
+			// The age of the comment is unknown, it can't be selected by a date criterion
+			continue
+		}
+		if !f.CreatedAfter.IsZero() && !created.After(f.CreatedAfter) {
+			continue
+		}
+		if !f.CreatedBefore.IsZero() && !created.Before(f.CreatedBefore) {
+			continue
+		}
+		selected = append(selected, c)
+	}
+
+	return selected
+}
+
+func createdAt(c Comment) (time.Time, bool) {
+	t, err := helpers.ParseJiraTime(c.Created)
+	return t, err == nil
+}
@@ -0,0 +1,75 @@
+package commenting_test
+
+import (
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/adf"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/commenting"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/user"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var comments = []commenting.Comment{
+	{Id: "3", Author: &user.User{Name: "bot"}, Body: adf.RichText{Text: "Deployed to QA"}, Created: "2020-03-03T10:00:00.000+0000"},
+	{Id: "1", Author: &user.User{Name: "bot"}, Body: adf.RichText{Text: "Deployed to QA"}, Created: "2020-03-01T10:00:00.000+0000"},
+	{Id: "2", Author: &user.User{Name: "alice"}, Body: adf.RichText{Text: "Deployed to QA?"}, Created: "2020-03-02T10:00:00.000+0000"},
+	{Id: "4", Author: &user.User{Name: "bot"}, Body: adf.RichText{Text: "Looks good"}, Created: "2020-03-04T10:00:00.000+0000"},
+	{Id: "5", Author: &user.User{Name: "bot"}, Body: adf.RichText{Text: "Deployed to QA"}, Created: "2020-03-05T10:00:00.000+0000"},
+}
+
+func TestCommentFilter_Select(t *testing.T) {
+	t.Run("matching comments of the author are sorted from the oldest", func(t *testing.T) {
+		// Arrange
+		filter := commenting.CommentFilter{Author: "bot", Match: regexp.MustCompile("^Deployed")}
+
+		// Act
+		selected := selectIds(filter)
+
+		// Assert
+		assert.Equal(t, []string{"1", "3", "5"}, selected)
+	})
+
+	t.Run("the last comments are kept before applying the dates", func(t *testing.T) {
+		// Arrange
+		filter := commenting.CommentFilter{
+			Author:        "bot",
+			Match:         regexp.MustCompile("^Deployed"),
+			KeepLast:      2,
+			CreatedBefore: time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC),
+		}
+
+		// Act
+		selected := selectIds(filter)
+
+		// Assert
+		assert.Equal(t, []string{"1"}, selected)
+	})
+
+	t.Run("nothing is selected when more comments are kept than there are", func(t *testing.T) {
+		// Arrange
+		filter := commenting.CommentFilter{Author: "bot", Match: regexp.MustCompile("QA"), KeepLast: 5}
+
+		// Act
+		selected := selectIds(filter)
+
+		// Assert
+		assert.Empty(t, selected)
+	})
+}
+
+func selectIds(filter commenting.CommentFilter) []string {
+	matching := make([]commenting.Comment, 0)
+	for _, c := range comments {
+		if filter.Matches(c) {
+			matching = append(matching, c)
+		}
+	}
+
+	ids := make([]string, 0)
+	for _, c := range filter.Select(matching) {
+		ids = append(ids, c.Id)
+	}
+
+	return ids
+}
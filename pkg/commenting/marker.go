@@ -0,0 +1,96 @@
+package commenting
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	upsertMarkerPrefix = "jira-api-resource-upsert-"
+	upsertPropertyKey  = "jira-api-resource.upsert"
+)
+
+// Characters escaped in the marker: the ones not allowed in an anchor name, and the '_' which introduces the escapes
+var escapedMarkerChars = regexp.MustCompile("[^A-Za-z0-9-]")
+
+var (
+	upsertMarkerPattern = regexp.MustCompile(`\{anchor:` + upsertMarkerPrefix + `([A-Za-z0-9_-]*)\}`)
+	markerEscapePattern = regexp.MustCompile("_[0-9A-F]{2}")
+)
+
+// Returns the marker identifying the comment posted with the given upsert key. The marker is an anchor macro which
+// is rendered as an invisible element by Jira.
+func UpsertMarker(upsertKey string) string {
+	return fmt.Sprintf("{anchor:%s%s}", upsertMarkerPrefix, encodeMarkerKey(upsertKey))
+}
+
+// Encodes the upsert key as an anchor name. Each escaped byte is replaced by '_' followed by its hexadecimal value, so
+// that two different keys (i.e. 'a.b' and 'a-b') never give the same marker.
+func encodeMarkerKey(upsertKey string) string {
+	return escapedMarkerChars.ReplaceAllStringFunc(upsertKey, func(s string) string {
+		var escaped strings.Builder
+		for _, b := range []byte(s) {
+			_, _ = fmt.Fprintf(&escaped, "_%02X", b)
+		}
+		return escaped.String()
+	})
+}
+
+// Decodes the anchor name written by encodeMarkerKey back into the upsert key
+func decodeMarkerKey(encoded string) string {
+	return markerEscapePattern.ReplaceAllStringFunc(encoded, func(s string) string {
+		b, _ := strconv.ParseUint(s[1:], 16, 8)
+		return string([]byte{byte(b)})
+	})
+}
+
+// Appends the upsert marker to the comment body. The body is returned untouched if the key is empty.
+func AppendUpsertMarker(body, upsertKey string) string {
+	if upsertKey == "" {
+		return body
+	}
+
+	return body + "\n" + UpsertMarker(upsertKey)
+}
+
+// Returns the comment property identifying the comment posted with the given upsert key. Atlassian Document Format
+// bodies (API version 3) and the plain text bodies of the Jira Service Management API have no invisible element, the
+// key is therefore stored as a property of the comment.
+func UpsertProperty(upsertKey string) EntityProperty {
+	return EntityProperty{Key: upsertPropertyKey, Value: map[string]string{"key": upsertKey}}
+}
+
+// Returns true if the comment bears the marker, or the property, of the given upsert key.
+func HasUpsertMarker(c Comment, upsertKey string) bool {
+	if upsertKey == "" {
+		return false
+	}
+
+	for _, p := range c.Properties {
+		if value, ok := p.Value.(map[string]interface{}); ok && p.Key == upsertPropertyKey && value["key"] == upsertKey {
+			return true
+		}
+	}
+
+	return strings.Contains(c.Body.String(), UpsertMarker(upsertKey))
+}
+
+// Returns the upsert key of the marker, or of the property, borne by the comment. An empty string is returned if the
+// comment bears neither.
+func UpsertKeyOf(c Comment) string {
+	for _, p := range c.Properties {
+		if value, ok := p.Value.(map[string]interface{}); ok && p.Key == upsertPropertyKey {
+			if key, ok := value["key"].(string); ok {
+				return key
+			}
+		}
+	}
+
+	if m := upsertMarkerPattern.FindStringSubmatch(c.Body.String()); m != nil {
+		return decodeMarkerKey(m[1])
+	}
+
+	return ""
+}
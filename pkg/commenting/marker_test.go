@@ -0,0 +1,105 @@
+package commenting_test
+
+import (
+	"encoding/json"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/adf"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/commenting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var upsertKeys = []string{"deploy", "a-b", "a.b", "a/b", "a_b", "a_2Eb", "build #42", "déploiement"}
+
+func TestUpsertMarker(t *testing.T) {
+	t.Run("a comment is found with the key it was posted with", func(t *testing.T) {
+		for _, key := range upsertKeys {
+			// Arrange
+			c := commenting.Comment{Body: adf.RichText{Text: commenting.AppendUpsertMarker("Deployed to QA", key)}}
+
+			// Act
+			found := commenting.HasUpsertMarker(c, key)
+
+			// Assert
+			assert.True(t, found, key)
+		}
+	})
+
+	t.Run("two different keys never give the same marker", func(t *testing.T) {
+		markers := make(map[string]string)
+		for _, key := range upsertKeys {
+			marker := commenting.UpsertMarker(key)
+
+			if other, ok := markers[marker]; ok {
+				t.Errorf("the keys %q and %q give the same marker %s", key, other, marker)
+			}
+			markers[marker] = key
+		}
+	})
+
+	t.Run("a comment is not found with another key", func(t *testing.T) {
+		// Arrange
+		c := commenting.Comment{Body: adf.RichText{Text: commenting.AppendUpsertMarker("Deployed to QA", "a.b")}}
+
+		// Act & Assert
+		for _, key := range []string{"a-b", "a/b", "a_b", "a", "a.bc"} {
+			assert.False(t, commenting.HasUpsertMarker(c, key), key)
+		}
+	})
+
+	t.Run("a keyless marker is never found", func(t *testing.T) {
+		c := commenting.Comment{Body: adf.RichText{Text: commenting.AppendUpsertMarker("Deployed to QA", "")}}
+
+		assert.Equal(t, "Deployed to QA", c.Body.Text)
+		assert.False(t, commenting.HasUpsertMarker(c, ""))
+	})
+}
+
+func TestUpsertProperty(t *testing.T) {
+	t.Run("a comment read from the API version 3 is found by its property", func(t *testing.T) {
+		// Arrange: the property is read back as a generic JSON object
+		property, err := json.Marshal(commenting.UpsertProperty("a.b"))
+		require.NoError(t, err)
+
+		var c commenting.Comment
+		body := `{"id":"1","body":{"type":"doc","version":1,"content":[]},"properties":[` + string(property) + `]}`
+		require.NoError(t, json.Unmarshal([]byte(body), &c))
+
+		// Act & Assert
+		assert.True(t, commenting.HasUpsertMarker(c, "a.b"))
+		assert.False(t, commenting.HasUpsertMarker(c, "a-b"))
+	})
+
+	t.Run("another property is ignored", func(t *testing.T) {
+		var c commenting.Comment
+		body := `{"id":"1","body":"Deployed","properties":[{"key":"other","value":{"key":"a.b"}}]}`
+		require.NoError(t, json.Unmarshal([]byte(body), &c))
+
+		assert.False(t, commenting.HasUpsertMarker(c, "a.b"))
+	})
+}
+
+func TestUpsertKeyOf(t *testing.T) {
+	t.Run("the key is decoded from the marker", func(t *testing.T) {
+		for _, key := range upsertKeys {
+			c := commenting.Comment{Body: adf.RichText{Text: commenting.AppendUpsertMarker("Deployed to QA", key)}}
+
+			assert.Equal(t, key, commenting.UpsertKeyOf(c), key)
+		}
+	})
+
+	t.Run("the key is read from the property", func(t *testing.T) {
+		var c commenting.Comment
+		property, err := json.Marshal(commenting.UpsertProperty("a.b"))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal([]byte(`{"id":"1","body":"Deployed","properties":[`+string(property)+`]}`), &c))
+
+		assert.Equal(t, "a.b", commenting.UpsertKeyOf(c))
+	})
+
+	t.Run("a comment without marker has no key", func(t *testing.T) {
+		c := commenting.Comment{Body: adf.RichText{Text: "Deployed to QA"}}
+
+		assert.Empty(t, commenting.UpsertKeyOf(c))
+	})
+}
@@ -0,0 +1,55 @@
+package commenting
+
+import (
+	"encoding/json"
+	"fmt"
+	resulthelper "github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/result"
+)
+
+// This struct is the representation of a comment written in the destination by the 'ReadComments' context.
+type CommentOutput struct {
+	Id       string `json:"id"`
+	Author   string `json:"author"`
+	AuthorId string `json:"authorId"`
+	Created  string `json:"created"`
+	Updated  string `json:"updated"`
+	Body     string `json:"body"`
+}
+
+// Writes the comments in two files: a JSON file (the list of CommentOutput) and a plain text file where each comment
+// is preceded by its date and author.
+func writeCommentsToFiles(destination string, comments []Comment) error {
+	outputs := make([]CommentOutput, 0, len(comments))
+	for _, c := range comments {
+		o := CommentOutput{Id: c.Id, Created: c.Created, Updated: c.Updated, Body: c.Body.String()}
+		if c.Author != nil {
+			o.Author = c.Author.DisplayName
+			o.AuthorId = c.Author.Id()
+		}
+		outputs = append(outputs, o)
+	}
+
+	jsonFile, err := resulthelper.CreateDestination(destination, "json")
+	if err != nil {
+		return err
+	}
+	defer jsonFile.Close()
+
+	if err := json.NewEncoder(jsonFile).Encode(outputs); err != nil {
+		return err
+	}
+
+	textFile, err := resulthelper.CreateDestination(destination, "txt")
+	if err != nil {
+		return err
+	}
+	defer textFile.Close()
+
+	for _, o := range outputs {
+		if err := resulthelper.Write(textFile, fmt.Sprintf("[%s] %s:\n", o.Created, o.Author), o.Body, "\n\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,26 +1,78 @@
 package commenting
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/adf"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/markup"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
 	"net/http"
 )
 
+// The ServiceAddComment struct implements the service.IterativeService interface. It posts a new comment on an issue
+// or, when an upsert key is specified and a previous comment was found by ServiceFindComment, edits that comment in
+// place. Comments on Jira Service Management requests can be posted as internal or customer-facing comments.
+//
+// The plain text bodies of the Jira Service Management API have no invisible element and the customers would see the
+// upsert marker: the upsert key of such a comment is stored as a property of the comment once it is posted.
 type ServiceAddComment struct {
 	issueId     string
 	commentBody string
+	upsertKey   string
+	commentId   string
+	visibility  *Visibility
+	serviceDesk string
+	asDocument  bool
+	dryRun      bool
+
+	// Id of the comment posted through the Jira Service Management API, whose upsert property is set by the next call
+	createdId string
+	done      bool
 }
 
 func (s *ServiceAddComment) InitJiraAPI(params configuration.JiraAPIResourceParameters) (rest.JiraAPI, error) {
 	s.issueId = params.ActiveIssue
 	s.commentBody = *params.AddComment.CommentBody
+	s.upsertKey = *params.AddComment.CommentUpsertKey
+	s.serviceDesk = helpers.StringPtrValue(params.AddComment.CommentServiceDesk)
+	s.visibility = nil
+	s.asDocument = params.IsAPIVersion(configuration.APIVersion3)
+	s.dryRun = helpers.IsBoolPtrTrue(params.Flags.DryRun)
+
+	if !s.asDocument {
+		s.commentBody = markup.ToWiki(s.commentBody, params)
+	}
+
+	if !helpers.IsStringPtrNilOrEmtpy(params.AddComment.CommentVisibilityType) {
+		s.visibility = &Visibility{
+			Type:  *params.AddComment.CommentVisibilityType,
+			Value: helpers.StringPtrValue(params.AddComment.CommentVisibilityValue),
+		}
+	}
+
+	if s.commentId != "" || s.createdId != "" {
+		return service.PreInitJiraAPI(s, params, http.MethodPut)
+	}
 
 	return service.PreInitJiraAPI(s, params, http.MethodPost)
 }
 
+// See service/service.go for details
+func (s *ServiceAddComment) Reset() {
+	s.createdId = ""
+	s.done = false
+}
+
+// See service/service.go for details
+func (s *ServiceAddComment) HasNext() bool {
+	return !s.done
+}
+
 // See service/service.go for details
 func (s *ServiceAddComment) GetResults() map[string]string {
 	return nil
@@ -28,16 +80,42 @@ func (s *ServiceAddComment) GetResults() map[string]string {
 
 // See service/service.go for details
 func (s *ServiceAddComment) SetResultsFromPrevious(result map[string]string) {
+	s.commentId = result[helpers.CommentIdKey]
 }
 
 // See service/service.go for details
 func (s *ServiceAddComment) GetEndpoint(url string) string {
+	if s.createdId != "" {
+		return fmt.Sprintf("%s/comment/%s/properties/%s", url, s.createdId, upsertPropertyKey)
+	} else if s.commentId != "" {
+		return fmt.Sprintf("%s/issue/%s/comment/%s", url, s.issueId, s.commentId)
+	} else if s.serviceDesk != "" {
+		return fmt.Sprintf("%s/request/%s/comment", rest.ServiceDeskURL(url), s.issueId)
+	}
+
 	return fmt.Sprintf("%s/issue/%s/comment", url, s.issueId)
 }
 
 // See service/service.go for details
 func (s *ServiceAddComment) CreateRequestBody() []byte {
-	c := Comment{Body: s.commentBody}
+	var c interface{}
+
+	if s.createdId != "" {
+		c = UpsertProperty(s.upsertKey).Value
+	} else if s.serviceDesk != "" && s.commentId == "" {
+		// The service desk API only accepts plain text bodies, whatever the version of the Jira API
+		c = ServiceDeskComment{Body: s.commentBody, Public: s.serviceDesk == configuration.ServiceDeskPublic}
+	} else if s.asDocument || s.serviceDesk != "" {
+		// An existing comment is always edited through the regular API, which keeps its service desk visibility
+		comment := Comment{Body: adf.NewRichText(s.commentBody, s.asDocument), Visibility: s.visibility}
+		if s.upsertKey != "" {
+			comment.Properties = []EntityProperty{UpsertProperty(s.upsertKey)}
+		}
+		c = comment
+	} else {
+		body := AppendUpsertMarker(s.commentBody, s.upsertKey)
+		c = Comment{Body: adf.NewRichText(body, false), Visibility: s.visibility}
+	}
 
 	b, err := json.Marshal(c)
 	if err != nil {
@@ -50,14 +128,55 @@ func (s *ServiceAddComment) CreateRequestBody() []byte {
 
 // See service/service.go for details
 func (s *ServiceAddComment) JSONResponseObject() interface{} {
+	if s.postsServiceDeskUpsert() {
+		return &ServiceDeskCommentCreated{}
+	}
+
 	return nil
 }
 
 // See service/service.go for details
 func (s *ServiceAddComment) PostAPICall(result interface{}) error {
+	if !s.postsServiceDeskUpsert() {
+		s.done = true
+		return nil
+	}
+
+	created, ok := result.(*ServiceDeskCommentCreated)
+	if !ok {
+		return errors.New("failed to convert result of type interface{} to comment of type commenting.ServiceDeskCommentCreated")
+	} else if created.Id == "" {
+		// The comment of a dry run is not posted
+		s.done = s.dryRun
+		if !s.done {
+			return errors.New("the id of the comment posted through the service desk API is missing")
+		}
+		return nil
+	}
+
+	s.createdId = created.Id
 	return nil
 }
 
+// A new comment posted with an upsert key can be found back by its marker, which tells whether a failed attempt was
+// nonetheless posted. Without upsert key, or when an existing comment is edited (PUT), there is nothing to check. A
+// comment posted through the service desk API bears its key only once its property is set by the next call, so it
+// can't be found back either.
+func (s *ServiceAddComment) IdempotencyCheck(params configuration.JiraAPIResourceParameters) rest.IdempotencyCheckFN {
+	if s.upsertKey == "" || s.commentId != "" || s.serviceDesk != "" {
+		return nil
+	}
+
+	return func(ctx context.Context, client *rest.Client) (bool, error) {
+		finder := &ServiceFindComment{}
+		if err := service.Execute(ctx, client, finder, params, false); err != nil {
+			return false, err
+		}
+
+		return finder.GetResults()[helpers.CommentIdKey] != "", nil
+	}
+}
+
 func (s *ServiceAddComment) Name() string {
 	return "ServiceAddComment"
 }
@@ -65,3 +184,8 @@ func (s *ServiceAddComment) Name() string {
 func (s *ServiceAddComment) ExecuteAsLastStep(params configuration.JiraAPIResourceParameters) error {
 	return nil
 }
+
+// Returns true if the call posts a new comment with an upsert key through the service desk API
+func (s *ServiceAddComment) postsServiceDeskUpsert() bool {
+	return s.serviceDesk != "" && s.upsertKey != "" && s.commentId == "" && s.createdId == ""
+}
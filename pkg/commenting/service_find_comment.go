@@ -0,0 +1,96 @@
+package commenting
+
+import (
+	"errors"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"net/http"
+)
+
+const commentsPageSize = 50
+
+// The ServiceFindComment struct implements the service.IterativeService interface. It goes through every page of
+// comments of an issue to find the one that was previously posted with the specified upsert key.
+type ServiceFindComment struct {
+	issueId   string
+	upsertKey string
+	startAt   int
+	hasNext   bool
+	commentId string
+}
+
+func (s *ServiceFindComment) InitJiraAPI(params configuration.JiraAPIResourceParameters) (rest.JiraAPI, error) {
+	s.issueId = params.ActiveIssue
+	s.upsertKey = *params.AddComment.CommentUpsertKey
+
+	return service.PreInitJiraAPI(s, params, http.MethodGet)
+}
+
+// See service/service.go for details
+func (s *ServiceFindComment) Reset() {
+	s.startAt = 0
+	s.hasNext = true
+	s.commentId = ""
+}
+
+// See service/service.go for details
+func (s *ServiceFindComment) HasNext() bool {
+	return s.hasNext
+}
+
+// See service/service.go for details
+func (s *ServiceFindComment) GetResults() map[string]string {
+	var m = make(map[string]string)
+	m[helpers.CommentIdKey] = s.commentId
+	return m
+}
+
+// See service/service.go for details
+func (s *ServiceFindComment) SetResultsFromPrevious(result map[string]string) {
+}
+
+// See service/service.go for details
+func (s *ServiceFindComment) GetEndpoint(url string) string {
+	return fmt.Sprintf("%s/issue/%s/comment?startAt=%d&maxResults=%d&expand=properties", url, s.issueId, s.startAt, commentsPageSize)
+}
+
+// See service/service.go for details
+func (s *ServiceFindComment) CreateRequestBody() []byte {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServiceFindComment) JSONResponseObject() interface{} {
+	return &Comments{}
+}
+
+// See service/service.go for details
+func (s *ServiceFindComment) PostAPICall(result interface{}) error {
+	page, ok := result.(*Comments)
+	if !ok {
+		return errors.New("failed to convert result of type interface{} to comments of type commenting.Comments")
+	}
+
+	for _, c := range page.Comments {
+		if HasUpsertMarker(c, s.upsertKey) {
+			// The most recent comment bearing the marker is the one that gets edited
+			s.commentId = c.Id
+		}
+	}
+
+	s.startAt = page.StartAt + len(page.Comments)
+	s.hasNext = len(page.Comments) > 0 && s.startAt < page.Total
+
+	return nil
+}
+
+func (s *ServiceFindComment) Name() string {
+	return "ServiceFindComment"
+}
+
+func (s *ServiceFindComment) ExecuteAsLastStep(params configuration.JiraAPIResourceParameters) error {
+	return nil
+}
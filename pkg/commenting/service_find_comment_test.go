@@ -0,0 +1,96 @@
+package commenting_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/commenting"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/jiratest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+// Returns a comment of the fake Jira with a plain text body
+func textComment(id, text string) jiratest.Comment {
+	body, _ := json.Marshal(text)
+	return jiratest.Comment{Id: id, Author: jiratest.DefaultUser(), Body: body, Created: "2020-03-01T10:00:00.000+0000"}
+}
+
+func newTestClient(server *jiratest.Server) *rest.Client {
+	return &rest.Client{BaseURL: server.APIURL(), HTTPClient: server.Client()}
+}
+
+func findComment(t *testing.T, server *jiratest.Server, upsertKey, apiVersion string) string {
+	params := configuration.JiraAPIResourceParameters{ActiveIssue: "ABC-1", APIVersion: &apiVersion}
+	params.AddComment.CommentUpsertKey = &upsertKey
+
+	s := &commenting.ServiceFindComment{}
+	require.NoError(t, service.Execute(context.Background(), newTestClient(server), s, params, false))
+
+	return s.GetResults()[helpers.CommentIdKey]
+}
+
+func TestServiceFindComment(t *testing.T) {
+	t.Run("the comment bearing the marker is found on the second page", func(t *testing.T) {
+		// Arrange: 60 comments, the one bearing the marker is on the second page of 50
+		server := jiratest.NewServer()
+		defer server.Close()
+
+		var comments []jiratest.Comment
+		for i := 1; i <= 60; i++ {
+			text := fmt.Sprintf("Comment %d", i)
+			if i == 55 {
+				text = commenting.AppendUpsertMarker("Deployed to QA", "deploy.qa")
+			}
+			comments = append(comments, textComment(fmt.Sprint(i), text))
+		}
+		server.AddIssue(jiratest.Issue{Key: "ABC-1", Comments: comments})
+
+		// Act
+		id := findComment(t, server, "deploy.qa", configuration.APIVersion2)
+
+		// Assert
+		assert.Equal(t, "55", id)
+		assert.Len(t, server.RequestsTo(http.MethodGet, "/issue/ABC-1/comment"), 2)
+	})
+
+	t.Run("a comment bearing the marker of a similar key is not found", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+
+		server.AddIssue(jiratest.Issue{Key: "ABC-1", Comments: []jiratest.Comment{
+			textComment("1", commenting.AppendUpsertMarker("Deployed to QA", "deploy-qa")),
+		}})
+
+		assert.Empty(t, findComment(t, server, "deploy.qa", configuration.APIVersion2))
+	})
+
+	for _, version := range []string{configuration.APIVersion2, configuration.APIVersion3} {
+		t.Run("the comment bearing the property is found with the API version "+version, func(t *testing.T) {
+			// Arrange
+			server := jiratest.NewServer()
+			defer server.Close()
+
+			property, _ := json.Marshal(commenting.UpsertProperty("deploy.qa").Value)
+			marked := textComment("2", "Deployed to QA")
+			marked.Properties = []jiratest.CommentProperty{{Key: "jira-api-resource.upsert", Value: property}}
+			server.AddIssue(jiratest.Issue{Key: "ABC-1", Comments: []jiratest.Comment{textComment("1", "Deployed to QA"), marked}})
+
+			// Act
+			id := findComment(t, server, "deploy.qa", version)
+
+			// Assert: the properties are only returned when they are expanded
+			assert.Equal(t, "2", id)
+			requests := server.RequestsTo(http.MethodGet, "/issue/ABC-1/comment")
+			if assert.Len(t, requests, 1) {
+				assert.Equal(t, "properties", requests[0].Query.Get("expand"))
+			}
+		})
+	}
+}
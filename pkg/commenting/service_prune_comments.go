@@ -0,0 +1,128 @@
+package commenting
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/adf"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/markup"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"net/http"
+	"strings"
+)
+
+// The ServicePruneComments struct implements the service.IterativeService interface. It deletes, or edits, every
+// comment selected by ServiceReadComments; one API call per comment. An edited comment keeps its upsert key, as a
+// property whatever the version of the API, so that it can still be found by a later upsert. A marker would be visible
+// in the comments of the Jira Service Management customers.
+type ServicePruneComments struct {
+	issueId    string
+	action     string
+	body       string
+	asDocument bool
+	commentIds []string
+	upsertKeys []string
+	index      int
+}
+
+// See service/service.go for details
+func (s *ServicePruneComments) InitJiraAPI(params configuration.JiraAPIResourceParameters) (rest.JiraAPI, error) {
+	s.issueId = params.ActiveIssue
+	s.action = helpers.StringPtrValue(params.PruneComments.Action)
+
+	if s.action == configuration.PruneEdit {
+		s.body = helpers.StringPtrValue(params.AddComment.CommentBody)
+		s.asDocument = params.IsAPIVersion(configuration.APIVersion3)
+
+		if !s.asDocument {
+			s.body = markup.ToWiki(s.body, params)
+		}
+		return service.PreInitJiraAPI(s, params, http.MethodPut)
+	}
+
+	return service.PreInitJiraAPI(s, params, http.MethodDelete)
+}
+
+// See service/service.go for details
+func (s *ServicePruneComments) Reset() {
+	s.index = 0
+}
+
+// See service/service.go for details
+func (s *ServicePruneComments) HasNext() bool {
+	return s.index < len(s.commentIds)
+}
+
+// See service/service.go for details
+func (s *ServicePruneComments) GetResults() map[string]string {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServicePruneComments) SetResultsFromPrevious(result map[string]string) {
+	s.commentIds = make([]string, 0)
+	s.upsertKeys = make([]string, 0)
+
+	markers := strings.Split(result[helpers.CommentMarkersKey], ",")
+	for i, id := range strings.Split(result[helpers.CommentIdsKey], ",") {
+		if id == "" {
+			continue
+		}
+
+		s.commentIds = append(s.commentIds, id)
+		if i < len(markers) {
+			s.upsertKeys = append(s.upsertKeys, decodeMarkerKey(markers[i]))
+		} else {
+			s.upsertKeys = append(s.upsertKeys, "")
+		}
+	}
+}
+
+// See service/service.go for details
+func (s *ServicePruneComments) GetEndpoint(url string) string {
+	return fmt.Sprintf("%s/issue/%s/comment/%s", url, s.issueId, s.commentIds[s.index])
+}
+
+// See service/service.go for details
+func (s *ServicePruneComments) CreateRequestBody() []byte {
+	if s.action != configuration.PruneEdit {
+		return nil
+	}
+
+	c := Comment{Body: adf.NewRichText(s.body, s.asDocument)}
+	if upsertKey := s.upsertKeys[s.index]; upsertKey != "" {
+		c.Properties = []EntityProperty{UpsertProperty(upsertKey)}
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		b, _ := json.Marshal(Comment{})
+		return b
+	}
+
+	return b
+}
+
+// See service/service.go for details
+func (s *ServicePruneComments) JSONResponseObject() interface{} {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServicePruneComments) PostAPICall(result interface{}) error {
+	log.Logger.Infof("Pruned (%s) comment %s of issue %s", s.action, s.commentIds[s.index], s.issueId)
+	s.index++
+
+	return nil
+}
+
+func (s *ServicePruneComments) Name() string {
+	return "ServicePruneComments"
+}
+
+func (s *ServicePruneComments) ExecuteAsLastStep(params configuration.JiraAPIResourceParameters) error {
+	return nil
+}
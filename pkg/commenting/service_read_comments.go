@@ -0,0 +1,178 @@
+package commenting
+
+import (
+	"errors"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// The ServiceReadComments struct implements the service.IterativeService interface. It reads every page of comments
+// of an issue and selects the ones matching the filter built from the parameters of the invoking context. In the
+// 'ReadComments' context, the selected comments are written as JSON and plain text in the destination.
+type ServiceReadComments struct {
+	issueId     string
+	destination string
+	filter      CommentFilter
+	myselfId    string
+	startAt     int
+	hasNext     bool
+	matching    []Comment
+	selected    []Comment
+}
+
+// See service/service.go for details
+func (s *ServiceReadComments) InitJiraAPI(params configuration.JiraAPIResourceParameters) (rest.JiraAPI, error) {
+	s.issueId = params.ActiveIssue
+	s.destination = helpers.StringPtrValue(params.Destination)
+
+	if err := s.initFilter(params); err != nil {
+		return rest.JiraAPI{}, err
+	}
+
+	return service.PreInitJiraAPI(s, params, http.MethodGet)
+}
+
+// See service/service.go for details
+func (s *ServiceReadComments) Reset() {
+	s.startAt = 0
+	s.hasNext = true
+	s.matching = make([]Comment, 0)
+	s.selected = make([]Comment, 0)
+}
+
+// See service/service.go for details
+func (s *ServiceReadComments) HasNext() bool {
+	return s.hasNext
+}
+
+// See service/service.go for details
+func (s *ServiceReadComments) GetResults() map[string]string {
+	ids := make([]string, 0, len(s.selected))
+	markers := make([]string, 0, len(s.selected))
+	for _, c := range s.selected {
+		ids = append(ids, c.Id)
+		markers = append(markers, encodeMarkerKey(UpsertKeyOf(c)))
+	}
+
+	var m = make(map[string]string)
+	m[helpers.CommentIdsKey] = strings.Join(ids, ",")
+	m[helpers.CommentMarkersKey] = strings.Join(markers, ",")
+	return m
+}
+
+// See service/service.go for details
+func (s *ServiceReadComments) SetResultsFromPrevious(result map[string]string) {
+	s.myselfId = result[helpers.MyselfIdKey]
+}
+
+// See service/service.go for details
+func (s *ServiceReadComments) GetEndpoint(url string) string {
+	return fmt.Sprintf("%s/issue/%s/comment?startAt=%d&maxResults=%d&expand=properties", url, s.issueId, s.startAt, commentsPageSize)
+}
+
+// See service/service.go for details
+func (s *ServiceReadComments) CreateRequestBody() []byte {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServiceReadComments) JSONResponseObject() interface{} {
+	return &Comments{}
+}
+
+// See service/service.go for details
+func (s *ServiceReadComments) PostAPICall(result interface{}) error {
+	page, ok := result.(*Comments)
+	if !ok {
+		return errors.New("failed to convert result of type interface{} to comments of type commenting.Comments")
+	}
+
+	for _, c := range page.Comments {
+		if s.filter.Matches(c) {
+			s.matching = append(s.matching, c)
+		}
+	}
+
+	s.startAt = page.StartAt + len(page.Comments)
+	s.hasNext = len(page.Comments) > 0 && s.startAt < page.Total
+
+	if !s.hasNext {
+		s.selected = s.filter.Select(s.matching)
+	}
+
+	return nil
+}
+
+func (s *ServiceReadComments) Name() string {
+	return "ServiceReadComments"
+}
+
+func (s *ServiceReadComments) ExecuteAsLastStep(params configuration.JiraAPIResourceParameters) error {
+	if params.Context != configuration.ReadComments || s.destination == "" {
+		return nil
+	}
+
+	return writeCommentsToFiles(s.destination+"_"+s.issueId+"_comments", s.selected)
+}
+
+func (s *ServiceReadComments) initFilter(params configuration.JiraAPIResourceParameters) error {
+	s.filter = CommentFilter{}
+
+	switch params.Context {
+	case configuration.ReadComments:
+		read := params.ReadComments
+		s.filter.Author = helpers.StringPtrValue(read.Author)
+
+		if !helpers.IsStringPtrNilOrEmtpy(read.Match) {
+			match, err := regexp.Compile(*read.Match)
+			if err != nil {
+				return err
+			}
+			s.filter.Match = match
+		}
+
+		if !helpers.IsStringPtrNilOrEmtpy(read.Since) {
+			since, err := helpers.ParseTimeOrAge(*read.Since, time.Now())
+			if err != nil {
+				return err
+			}
+			s.filter.CreatedAfter = since
+		}
+	case configuration.PruneComments:
+		if s.myselfId == "" {
+			return errors.New("the user the resource is connected as is unknown, no comment can be pruned")
+		}
+
+		prune := params.PruneComments
+		s.filter.Author = s.myselfId
+		s.filter.UpsertKey = helpers.StringPtrValue(params.AddComment.CommentUpsertKey)
+		if prune.KeepLast != nil {
+			s.filter.KeepLast = *prune.KeepLast
+		}
+
+		if !helpers.IsStringPtrNilOrEmtpy(prune.Match) {
+			match, err := regexp.Compile(*prune.Match)
+			if err != nil {
+				return err
+			}
+			s.filter.Match = match
+		}
+
+		if !helpers.IsStringPtrNilOrEmtpy(prune.OlderThan) {
+			age, err := helpers.ParseDuration(*prune.OlderThan)
+			if err != nil {
+				return err
+			}
+			s.filter.CreatedBefore = time.Now().Add(-age)
+		}
+	}
+
+	return nil
+}
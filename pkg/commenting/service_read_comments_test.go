@@ -0,0 +1,123 @@
+package commenting_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/commenting"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/jiratest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var alice = jiratest.User{AccountId: "alice-id", DisplayName: "Alice"}
+
+// Seeds 70 comments, one a day from 2020-03-02: the odd ones are written by Alice and the bot writes the even ones.
+// Every third comment is a review, the others are deploy notifications.
+func seedComments(server *jiratest.Server) {
+	var comments []jiratest.Comment
+	for i := 1; i <= 70; i++ {
+		text := fmt.Sprintf("Deployed to QA #%d", i)
+		if i%3 == 0 {
+			text = "Looks good"
+		}
+
+		c := textComment(fmt.Sprint(i), text)
+		c.Created = time.Date(2020, 3, 1+i, 10, 0, 0, 0, time.UTC).Format("2006-01-02T15:04:05.000-0700")
+		c.Updated = c.Created
+		if i%2 == 1 {
+			c.Author = alice
+		}
+		comments = append(comments, c)
+	}
+
+	server.AddIssue(jiratest.Issue{Key: "ABC-1", Comments: comments})
+}
+
+func readComments(t *testing.T, server *jiratest.Server, destination, author, since, match string) {
+	params := configuration.JiraAPIResourceParameters{
+		ActiveIssue: "ABC-1",
+		Context:     configuration.ReadComments,
+		Destination: &destination,
+	}
+	params.ReadComments.Author = &author
+	params.ReadComments.Since = &since
+	params.ReadComments.Match = &match
+
+	require.NoError(t, service.Execute(context.Background(), newTestClient(server), &commenting.ServiceReadComments{}, params, true))
+}
+
+func TestServiceReadComments(t *testing.T) {
+	dir, err := ioutil.TempDir("", "comments")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("the comments of every page matching the filters are written", func(t *testing.T) {
+		// Arrange
+		server := jiratest.NewServer()
+		defer server.Close()
+		seedComments(server)
+		destination := filepath.Join(dir, "filtered")
+
+		// Act
+		readComments(t, server, destination, "Alice", "2020-04-01", "^Deployed")
+
+		// Assert: both pages were read
+		assert.Len(t, server.RequestsTo(http.MethodGet, "/issue/ABC-1/comment"), 2)
+
+		b, err := ioutil.ReadFile(destination + "_ABC-1_comments.json")
+		require.NoError(t, err)
+		var outputs []commenting.CommentOutput
+		require.NoError(t, json.Unmarshal(b, &outputs))
+
+		var ids []string
+		for _, o := range outputs {
+			ids = append(ids, o.Id)
+			assert.Equal(t, "Alice", o.Author)
+			assert.Equal(t, "alice-id", o.AuthorId)
+		}
+		assert.Equal(t, []string{"31", "35", "37", "41", "43", "47", "49", "53", "55", "59", "61", "65", "67"}, ids)
+
+		text, err := ioutil.ReadFile(destination + "_ABC-1_comments.txt")
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(text), "[2020-04-01T10:00:00.000+0000] Alice:\nDeployed to QA #31\n\n"), string(text))
+		assert.Equal(t, 13, strings.Count(string(text), "Deployed to QA #"))
+	})
+
+	t.Run("every comment is written without filter", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+		seedComments(server)
+		destination := filepath.Join(dir, "all")
+
+		readComments(t, server, destination, "", "", "")
+
+		b, err := ioutil.ReadFile(destination + "_ABC-1_comments.json")
+		require.NoError(t, err)
+		var outputs []commenting.CommentOutput
+		require.NoError(t, json.Unmarshal(b, &outputs))
+		assert.Len(t, outputs, 70)
+	})
+
+	t.Run("an empty list is written when no comment matches", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+		seedComments(server)
+		destination := filepath.Join(dir, "none")
+
+		readComments(t, server, destination, "nobody", "", "")
+
+		b, err := ioutil.ReadFile(destination + "_ABC-1_comments.json")
+		require.NoError(t, err)
+		assert.JSONEq(t, "[]", string(b))
+	})
+}
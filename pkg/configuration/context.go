@@ -13,10 +13,17 @@ const (
 	ReadStatus
 	EditCustomField
 	AddComment
+	PruneComments
+	ReadComments
+	ReadChangelog
+	SetIssueProperty
+	ReadIssueProperty
+	BulkCreate
 	Unknown
 )
 
-var names = [...]string{"ReadIssue", "ReadStatus", "EditCustomField", "AddComment", "Unknown"}
+var names = [...]string{"ReadIssue", "ReadStatus", "EditCustomField", "AddComment", "PruneComments", "ReadComments",
+	"ReadChangelog", "SetIssueProperty", "ReadIssueProperty", "BulkCreate", "Unknown"}
 
 // Returns the string value of the current Context
 func (c Context) String() string {
@@ -29,6 +36,12 @@ func (c Context) String() string {
 
 }
 
+// Returns true if the context is executed for each issue of the list, which is then required. The contexts creating
+// issues are executed only once.
+func (c Context) RequiresIssues() bool {
+	return c != BulkCreate
+}
+
 // This function is the implementation of the 'valueOf' mechanic of an enum-like construct
 func GetContext(contextString string) Context {
 	for index, name := range names {
@@ -18,12 +18,15 @@ The package also defines a wide array of constants representing the flags name i
 package configuration
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/auth"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
+	"regexp"
 	"strings"
+	"time"
 )
 
 // Definition of constants that are use for the flags setup
@@ -32,6 +35,35 @@ const (
 	jiraAPIURL               = "url"
 	username                 = "username"
 	password                 = "password"
+	authType                 = "authType"
+	token                    = "token"
+	consumerKey              = "consumerKey"
+	privateKey               = "privateKey"
+	accessToken              = "accessToken"
+	tokenSecret              = "tokenSecret"
+	clientId                 = "clientId"
+	clientSecret             = "clientSecret"
+	refreshToken             = "refreshToken"
+	tokenURL                 = "tokenUrl"
+	gatewayURL               = "gatewayUrl"
+	cloudId                  = "cloudId"
+	caCert                   = "caCert"
+	clientCert               = "clientCert"
+	clientKey                = "clientKey"
+	proxyURL                 = "proxyUrl"
+	noProxy                  = "noProxy"
+	headers                  = "headers"
+	userAgent                = "userAgent"
+	retries                  = "retries"
+	retryBaseDelay           = "retryBaseDelay"
+	retryMaxDelay            = "retryMaxDelay"
+	requestTimeout           = "requestTimeout"
+	timeout                  = "timeout"
+	parallelism              = "parallelism"
+	cassette                 = "cassette"
+	cassetteMode             = "cassetteMode"
+	apiVersion               = "apiVersion"
+	commentFormat            = "commentFormat"
 	destination              = "destination"
 	context                  = "context"
 	issueList                = "issues"
@@ -43,11 +75,31 @@ const (
 	closedStatusName         = "closedStatusName"
 	transitionName           = "transitionName"
 	commentBody              = "commentBody"
+	commentUpsertKey         = "commentUpsertKey"
+	commentVisibilityType    = "commentVisibilityType"
+	commentVisibilityValue   = "commentVisibilityValue"
+	commentServiceDesk       = "commentServiceDesk"
+	commentAuthor            = "commentAuthor"
+	commentSince             = "commentSince"
+	commentMatch             = "commentMatch"
+	doneStatuses             = "doneStatuses"
+	inProgressStatuses       = "inProgressStatuses"
+	propertyKey              = "propertyKey"
+	propertyValue            = "propertyValue"
+	propertyValueFromFile    = "propertyValueFromFile"
+	pruneMatch               = "pruneMatch"
+	pruneOlderThan           = "pruneOlderThan"
+	pruneKeepLast            = "pruneKeepLast"
+	pruneAction              = "pruneAction"
+	manifest                 = "manifest"
 
 	// Flags
-	forceOnParent    = "forceOnParent"
-	forceOpen        = "forceOpen"
-	keepGoingOnError = "keepGoing"
+	forceOnParent      = "forceOnParent"
+	forceOpen          = "forceOpen"
+	keepGoingOnError   = "keepGoing"
+	insecureSkipVerify = "insecureSkipVerify"
+	prefetch           = "prefetch"
+	dryRun             = "dryRun"
 
 	// Default values and descriptions for both paramaters and flags
 	jiraAPIURLDefault                   = ""
@@ -56,10 +108,68 @@ const (
 	usernameDescription                 = "The username used to connect to the Jira API"
 	passwordDefault                     = ""
 	passwordDescription                 = "The password needed to connect to the Jira API"
+	authTypeDefault                     = auth.TypeBasic
+	authTypeDescription                 = "The type of authentication used to connect to the Jira API. {'basic', 'bearer', 'oauth1', 'oauth2'}"
+	tokenDefault                        = ""
+	tokenDescription                    = "The personal access token used to connect to the Jira API (bearer authentication)"
+	consumerKeyDefault                  = ""
+	consumerKeyDescription              = "The consumer key of the application link (oauth1 authentication)"
+	privateKeyDefault                   = ""
+	privateKeyDescription               = "The PEM encoded RSA private key of the application link consumer (oauth1 authentication)"
+	accessTokenDefault                  = ""
+	accessTokenDescription              = "The access token authorized for the application link consumer (oauth1 authentication)"
+	tokenSecretDefault                  = ""
+	tokenSecretDescription              = "The secret of the access token (oauth1 authentication)"
+	clientIdDefault                     = ""
+	clientIdDescription                 = "The client id of the OAuth 2.0 integration or service account (oauth2 authentication)"
+	clientSecretDefault                 = ""
+	clientSecretDescription             = "The client secret of the OAuth 2.0 integration or service account (oauth2 authentication)"
+	refreshTokenDefault                 = ""
+	refreshTokenDescription             = "The refresh token of the OAuth 2.0 integration. The client credentials grant is used when empty (oauth2 authentication)"
+	tokenURLDefault                     = auth.DefaultOAuth2TokenURL
+	tokenURLDescription                 = "The endpoint from which OAuth 2.0 access tokens are obtained (oauth2 authentication)"
+	gatewayURLDefault                   = auth.DefaultOAuth2GatewayURL
+	gatewayURLDescription               = "The Atlassian API gateway through which the Jira API is reached (oauth2 authentication)"
+	cloudIdDefault                      = ""
+	cloudIdDescription                  = "The cloud id of the Jira site. Discovered from the accessible resources when empty (oauth2 authentication)"
+	caCertDefault                       = ""
+	caCertDescription                   = "PEM encoded certificate(s) trusted, in addition to the system ones, to verify the certificate of the Jira server"
+	clientCertDefault                   = ""
+	clientCertDescription               = "PEM encoded client certificate presented to servers requiring mutual TLS"
+	clientKeyDefault                    = ""
+	clientKeyDescription                = "PEM encoded private key of the client certificate"
+	proxyURLDefault                     = ""
+	proxyURLDescription                 = "The HTTP(S) proxy through which the Jira API is reached. The proxy environment variables are used when empty"
+	noProxyDefault                      = ""
+	noProxyDescription                  = "Comma-separated list of hosts, domains, IP addresses and CIDR ranges reached without the proxy"
+	headersDefault                      = ""
+	headersDescription                  = "JSON object of the extra headers sent with every request to the Jira API"
+	userAgentDefault                    = "jira-api-issue-resource"
+	userAgentDescription                = "The User-Agent header sent with every request to the Jira API"
+	retriesDefault                      = 3
+	retriesDescription                  = "Number of times a request is sent again after a network error, a rate-limit (HTTP 429) or a server error (HTTP 5xx)"
+	retryBaseDelayDefault               = "1s"
+	retryBaseDelayDescription           = "Delay before the first retry. It doubles on each retry unless Jira asks for a specific delay"
+	retryMaxDelayDefault                = "30s"
+	retryMaxDelayDescription            = "Maximum delay between two retries"
+	requestTimeoutDefault               = "60s"
+	requestTimeoutDescription           = "Maximum duration of a single request to the Jira API, reading the response included"
+	timeoutDefault                      = ""
+	timeoutDescription                  = "Maximum duration of the whole run. Once reached, the run is aborted (forced open issues are still closed back). No limit when empty"
+	parallelismDefault                  = 1
+	parallelismDescription              = "Number of issues of the list processed concurrently"
+	cassetteDefault                     = ""
+	cassetteDescription                 = "File in which every exchange with Jira is recorded (credentials scrubbed), or from which they are replayed"
+	cassetteModeDefault                 = CassetteReplay
+	cassetteModeDescription             = "Whether the exchanges are recorded in the cassette or replayed from it. {'record', 'replay'}"
+	apiVersionDefault                   = APIVersion2
+	apiVersionDescription               = "The version of the Jira REST API targeted by the URL. Version 3 uses Atlassian Document Format bodies. {'2', '3'}"
+	commentFormatDefault                = FormatPlain
+	commentFormatDescription            = "The format in which comments, descriptions and rich-text custom fields are written. Markdown is converted before being sent. {'plain', 'markdown'}"
 	destinationDefault                  = ""
 	destinationDescription              = "The destination to output new version(s) when using the 'in'"
 	contextDefault                      = ""
-	contextDescription                  = "The context of execution. {'ReadIssue', 'EditCustomField'}"
+	contextDescription                  = "The context of execution. {'ReadIssue', 'ReadStatus', 'EditCustomField', 'AddComment', 'PruneComments', 'ReadComments', 'ReadChangelog', 'SetIssueProperty', 'ReadIssueProperty', 'BulkCreate'}"
 	issueListDefault                    = ""
 	issueListDescription                = "The issue or list of issues to execute the specified context to"
 	customFieldNameDefault              = ""
@@ -78,12 +188,68 @@ const (
 	transitionNameDescription           = "The name (as written in Jira) of the desired nwe status."
 	commentBodyDefault                  = ""
 	commentBodyDescription              = "The text body of the comment that will be posted to specified issue(s)."
+	commentUpsertKeyDefault             = ""
+	commentUpsertKeyDescription         = "When specified, the comment previously posted with the same key is edited instead of posting a new one."
+	commentVisibilityTypeDefault        = ""
+	commentVisibilityTypeDescription    = "Restricts the visibility of the comment to a project role or a group. {'role', 'group'}"
+	commentVisibilityValueDefault       = ""
+	commentVisibilityValueDescription   = "The name of the project role or group that is allowed to see the comment."
+	commentServiceDeskDefault           = ""
+	commentServiceDeskDescription       = "Posts the comment through the Jira Service Management API as an internal or customer-facing comment. {'internal', 'public'}"
+	commentAuthorDefault                = ""
+	commentAuthorDescription            = "Only the comments of this author (account id, name, display name or email address) are read."
+	commentSinceDefault                 = ""
+	commentSinceDescription             = "Only the comments created after this date (i.e. '2020-02-18') or during this last period (i.e. '7d') are read."
+	commentMatchDefault                 = ""
+	commentMatchDescription             = "Regular expression that the body of the comments to read must match."
+	doneStatusesDefault                 = "Done,Closed,Resolved"
+	doneStatusesDescription             = "Comma-separated list of the statuses in which an issue is considered done (lead and cycle times)."
+	inProgressStatusesDefault           = "In Progress"
+	inProgressStatusesDescription       = "Comma-separated list of the statuses in which the work on an issue is considered started (cycle time)."
+	propertyKeyDefault                  = ""
+	propertyKeyDescription              = "The key of the issue entity property to set or read."
+	propertyValueDefault                = ""
+	propertyValueDescription            = "The JSON value of the issue entity property to set."
+	propertyValueFromFileDefault        = ""
+	propertyValueFromFileDescription    = "The JSON value, stored in a file, of the issue entity property to set."
+	pruneMatchDefault                   = ""
+	pruneMatchDescription               = "Regular expression that the body of the comments to prune must match."
+	pruneOlderThanDefault               = ""
+	pruneOlderThanDescription           = "Only the comments older than this age are pruned (i.e. '72h', '30d', '2w')."
+	pruneKeepLastDefault                = 0
+	pruneKeepLastDescription            = "Number of most recent matching comments that are never pruned."
+	pruneActionDefault                  = PruneDelete
+	pruneActionDescription              = "What is done to the pruned comments. When editing, their body is replaced by the 'commentBody' parameter. {'delete', 'edit'}"
+	manifestDefault                     = ""
+	manifestDescription                 = "Path of the YAML or CSV manifest of the issues to create in bulk."
 	_                                   = /*forceOnParentDefault*/ false
 	forceOnParentDescription            = "Flag that indicates if we want to force all operation on the parent issue (if there's one)"
 	_                                   = /*forceOpenDefault*/ false
 	forceOpenDescription                = "Flag that will, if the issue if closed, forcefully open it, apply the changes and then close it back"
 	_                                   = /*keepGoingOnErrorDefault*/ false
 	keepGoingOnErrorDescription         = "Flag that makes the pipeline continue even if an error occurs on a specific issue."
+	_                                   = /*insecureSkipVerifyDefault*/ false
+	insecureSkipVerifyDescription       = "Flag that disables the verification of the certificate of the Jira server. Not recommended."
+	_                                   = /*prefetchDefault*/ false
+	prefetchDescription                 = "Flag that reads all the issues of the list by batches through the search API before processing them."
+	_                                   = /*dryRunDefault*/ false
+	dryRunDescription                   = "Flag that logs the requests modifying Jira (PUT, POST, DELETE) instead of sending them."
+)
+
+// Accepted values of the comment visibility type and of the service desk comment parameters
+const (
+	VisibilityRole      = "role"
+	VisibilityGroup     = "group"
+	ServiceDeskInternal = "internal"
+	ServiceDeskPublic   = "public"
+	APIVersion2         = "2"
+	APIVersion3         = "3"
+	FormatPlain         = "plain"
+	FormatMarkdown      = "markdown"
+	PruneDelete         = "delete"
+	PruneEdit           = "edit"
+	CassetteRecord      = "record"
+	CassetteReplay      = "replay"
 )
 
 // JiraAPIResourceParameters is a struct that holds every possible parameters/flags known by the application.
@@ -93,6 +259,35 @@ type JiraAPIResourceParameters struct {
 	JiraAPIUrl       *string
 	Username         *string
 	Password         *string
+	AuthType         *string
+	Token            *string
+	ConsumerKey      *string
+	PrivateKey       *string
+	AccessToken      *string
+	TokenSecret      *string
+	ClientId         *string
+	ClientSecret     *string
+	RefreshToken     *string
+	TokenURL         *string
+	GatewayURL       *string
+	CloudId          *string
+	CACert           *string
+	ClientCert       *string
+	ClientKey        *string
+	ProxyURL         *string
+	NoProxy          *string
+	Headers          *string
+	UserAgent        *string
+	Retries          *int
+	RetryBaseDelay   *string
+	RetryMaxDelay    *string
+	RequestTimeout   *string
+	Timeout          *string
+	Parallelism      *int
+	Cassette         *string
+	CassetteMode     *string
+	APIVersion       *string
+	CommentFormat    *string
 	Destination      *string
 	Context          Context
 	IssueList        []string
@@ -103,6 +298,11 @@ type JiraAPIResourceParameters struct {
 	ReadIssueParam       JiraApiResourceParametersReadIssue
 	EditCustomFieldParam JiraApiResourceParametersEditCustomField
 	AddComment           JiraApiResourceParametersAddComment
+	PruneComments        JiraApiResourceParametersPruneComments
+	ReadComments         JiraApiResourceParametersReadComments
+	ReadChangelog        JiraApiResourceParametersReadChangelog
+	IssueProperty        JiraApiResourceParametersIssueProperty
+	BulkCreate           JiraApiResourceParametersBulkCreate
 
 	ActiveIssue string         // The **SINGLE** issue that the resource is currently processing
 	Meta        MetaParameters //
@@ -113,9 +313,12 @@ type JiraAPIResourceParameters struct {
 // That being said the values in this struct are still parsed via the Go flags api. They've been put 'aside' for clariry
 // purposes.
 type JiraAPIResourceFlags struct {
-	ForceOnParent    *bool
-	ForceOpen        *bool
-	KeepGoingOnError *bool
+	ForceOnParent      *bool
+	ForceOpen          *bool
+	KeepGoingOnError   *bool
+	InsecureSkipVerify *bool
+	Prefetch           *bool
+	DryRun             *bool
 }
 
 type JiraApiResourceParametersReadIssue struct {
@@ -132,7 +335,39 @@ type JiraApiResourceParametersEditCustomField struct {
 }
 
 type JiraApiResourceParametersAddComment struct {
-	CommentBody *string
+	CommentBody            *string
+	CommentUpsertKey       *string
+	CommentVisibilityType  *string
+	CommentVisibilityValue *string
+	CommentServiceDesk     *string
+}
+
+type JiraApiResourceParametersReadComments struct {
+	Author *string
+	Since  *string
+	Match  *string
+}
+
+type JiraApiResourceParametersReadChangelog struct {
+	DoneStatuses       *string
+	InProgressStatuses *string
+}
+
+type JiraApiResourceParametersIssueProperty struct {
+	PropertyKey           *string
+	PropertyValue         *string
+	PropertyValueFromFile *string
+}
+
+type JiraApiResourceParametersBulkCreate struct {
+	Manifest *string
+}
+
+type JiraApiResourceParametersPruneComments struct {
+	Match     *string
+	OlderThan *string
+	KeepLast  *int
+	Action    *string
 }
 
 // Method that initialize every parameters/flags and makes the actual call the flag.Parse().
@@ -144,6 +379,35 @@ func (param *JiraAPIResourceParameters) Parse() (*string, *string) {
 	param.JiraAPIUrl = flag.String(jiraAPIURL, jiraAPIURLDefault, jiraAPIURLDescription)
 	param.Username = flag.String(username, usernameDefault, usernameDescription)
 	param.Password = flag.String(password, passwordDefault, passwordDescription)
+	param.AuthType = flag.String(authType, authTypeDefault, authTypeDescription)
+	param.Token = flag.String(token, tokenDefault, tokenDescription)
+	param.ConsumerKey = flag.String(consumerKey, consumerKeyDefault, consumerKeyDescription)
+	param.PrivateKey = flag.String(privateKey, privateKeyDefault, privateKeyDescription)
+	param.AccessToken = flag.String(accessToken, accessTokenDefault, accessTokenDescription)
+	param.TokenSecret = flag.String(tokenSecret, tokenSecretDefault, tokenSecretDescription)
+	param.ClientId = flag.String(clientId, clientIdDefault, clientIdDescription)
+	param.ClientSecret = flag.String(clientSecret, clientSecretDefault, clientSecretDescription)
+	param.RefreshToken = flag.String(refreshToken, refreshTokenDefault, refreshTokenDescription)
+	param.TokenURL = flag.String(tokenURL, tokenURLDefault, tokenURLDescription)
+	param.GatewayURL = flag.String(gatewayURL, gatewayURLDefault, gatewayURLDescription)
+	param.CloudId = flag.String(cloudId, cloudIdDefault, cloudIdDescription)
+	param.CACert = flag.String(caCert, caCertDefault, caCertDescription)
+	param.ClientCert = flag.String(clientCert, clientCertDefault, clientCertDescription)
+	param.ClientKey = flag.String(clientKey, clientKeyDefault, clientKeyDescription)
+	param.ProxyURL = flag.String(proxyURL, proxyURLDefault, proxyURLDescription)
+	param.NoProxy = flag.String(noProxy, noProxyDefault, noProxyDescription)
+	param.Headers = flag.String(headers, headersDefault, headersDescription)
+	param.UserAgent = flag.String(userAgent, userAgentDefault, userAgentDescription)
+	param.Retries = flag.Int(retries, retriesDefault, retriesDescription)
+	param.RetryBaseDelay = flag.String(retryBaseDelay, retryBaseDelayDefault, retryBaseDelayDescription)
+	param.RetryMaxDelay = flag.String(retryMaxDelay, retryMaxDelayDefault, retryMaxDelayDescription)
+	param.RequestTimeout = flag.String(requestTimeout, requestTimeoutDefault, requestTimeoutDescription)
+	param.Timeout = flag.String(timeout, timeoutDefault, timeoutDescription)
+	param.Parallelism = flag.Int(parallelism, parallelismDefault, parallelismDescription)
+	param.Cassette = flag.String(cassette, cassetteDefault, cassetteDescription)
+	param.CassetteMode = flag.String(cassetteMode, cassetteModeDefault, cassetteModeDescription)
+	param.APIVersion = flag.String(apiVersion, apiVersionDefault, apiVersionDescription)
+	param.CommentFormat = flag.String(commentFormat, commentFormatDefault, commentFormatDescription)
 	param.Destination = flag.String(destination, destinationDefault, destinationDescription)
 	contextString = flag.String(context, contextDefault, contextDescription)
 	issueListString = flag.String(issueList, issueListDefault, issueListDescription)
@@ -152,6 +416,23 @@ func (param *JiraAPIResourceParameters) Parse() (*string, *string) {
 	param.EditCustomFieldParam.CustomFieldValue = flag.String(customFieldValueAsIs, customFieldValueAsIsDefault, customFieldValueAsIsDescription)
 	param.EditCustomFieldParam.CustomFieldValueFromFile = flag.String(customFieldValueFromFile, customFieldValueFromFileDefault, customFieldValueFromFileDescription)
 	param.AddComment.CommentBody = flag.String(commentBody, commentBodyDefault, commentBodyDescription)
+	param.AddComment.CommentUpsertKey = flag.String(commentUpsertKey, commentUpsertKeyDefault, commentUpsertKeyDescription)
+	param.AddComment.CommentVisibilityType = flag.String(commentVisibilityType, commentVisibilityTypeDefault, commentVisibilityTypeDescription)
+	param.AddComment.CommentVisibilityValue = flag.String(commentVisibilityValue, commentVisibilityValueDefault, commentVisibilityValueDescription)
+	param.AddComment.CommentServiceDesk = flag.String(commentServiceDesk, commentServiceDeskDefault, commentServiceDeskDescription)
+	param.ReadComments.Author = flag.String(commentAuthor, commentAuthorDefault, commentAuthorDescription)
+	param.ReadComments.Since = flag.String(commentSince, commentSinceDefault, commentSinceDescription)
+	param.ReadComments.Match = flag.String(commentMatch, commentMatchDefault, commentMatchDescription)
+	param.ReadChangelog.DoneStatuses = flag.String(doneStatuses, doneStatusesDefault, doneStatusesDescription)
+	param.ReadChangelog.InProgressStatuses = flag.String(inProgressStatuses, inProgressStatusesDefault, inProgressStatusesDescription)
+	param.IssueProperty.PropertyKey = flag.String(propertyKey, propertyKeyDefault, propertyKeyDescription)
+	param.IssueProperty.PropertyValue = flag.String(propertyValue, propertyValueDefault, propertyValueDescription)
+	param.IssueProperty.PropertyValueFromFile = flag.String(propertyValueFromFile, propertyValueFromFileDefault, propertyValueFromFileDescription)
+	param.PruneComments.Match = flag.String(pruneMatch, pruneMatchDefault, pruneMatchDescription)
+	param.PruneComments.OlderThan = flag.String(pruneOlderThan, pruneOlderThanDefault, pruneOlderThanDescription)
+	param.PruneComments.KeepLast = flag.Int(pruneKeepLast, pruneKeepLastDefault, pruneKeepLastDescription)
+	param.PruneComments.Action = flag.String(pruneAction, pruneActionDefault, pruneActionDescription)
+	param.BulkCreate.Manifest = flag.String(manifest, manifestDefault, manifestDescription)
 
 	param.LoggingLevel = flag.String(loggingLevel, loggingLevelDefault, loggingLevelDescription)
 	param.ClosedStatusName = flag.String(closedStatusName, closedStatusNameDefault, closedStatusNameDescription)
@@ -161,6 +442,9 @@ func (param *JiraAPIResourceParameters) Parse() (*string, *string) {
 	param.Flags.ForceOnParent = flag.Bool(forceOnParent, false, forceOnParentDescription)
 	param.Flags.ForceOpen = flag.Bool(forceOpen, false, forceOpenDescription)
 	param.Flags.KeepGoingOnError = flag.Bool(keepGoingOnError, false, keepGoingOnErrorDescription)
+	param.Flags.InsecureSkipVerify = flag.Bool(insecureSkipVerify, false, insecureSkipVerifyDescription)
+	param.Flags.Prefetch = flag.Bool(prefetch, false, prefetchDescription)
+	param.Flags.DryRun = flag.Bool(dryRun, false, dryRunDescription)
 
 	if !param.Meta.parsed {
 		flag.Parse()
@@ -183,12 +467,12 @@ func (param *JiraAPIResourceParameters) validate() {
 	param.Meta.mandatoryPresent = true
 	param.Meta.valid = true
 
-	if *param.JiraAPIUrl == "" || *param.Username == "" || *param.Password == "" {
+	if *param.JiraAPIUrl == "" || !param.credentialsPresent() {
 		// In this case we are missing one or more mandatory parameters
 		// This also causes the input parameters to not be valid
 		param.Meta.mandatoryPresent = false
 		param.Meta.valid = false
-	} else if param.IssueList == nil || len(param.IssueList) == 0 {
+	} else if (param.IssueList == nil || len(param.IssueList) == 0) && param.Context.RequiresIssues() {
 		// This case is either
 		//   - A nil issue list
 		//   - An issue list that was passed but is empty
@@ -198,11 +482,46 @@ func (param *JiraAPIResourceParameters) validate() {
 	} else if helpers.IsBoolPtrTrue(param.Flags.ForceOpen) && helpers.IsStringPtrNilOrEmtpy(param.TransitionName) {
 		param.Meta.valid = false
 		param.Meta.Msg = fmt.Sprintf("'%s' flag was specified yet the '%s' parameter was not. This is an invalid configuration.", forceOpen, transitionName)
+	} else if t := helpers.StringPtrValue(param.AuthType); t != "" && !isAuthType(t) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Unsupported '%s' parameter: %s", authType, t)
+	} else if !param.validateOAuth1() {
+		param.Meta.valid = false
+	} else if helpers.IsStringPtrNilOrEmtpy(param.ClientCert) != helpers.IsStringPtrNilOrEmtpy(param.ClientKey) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("The '%s' and '%s' parameters must be specified together", clientCert, clientKey)
+	} else if _, err := param.ExtraHeaders(); err != nil {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Invalid '%s' parameter, a JSON object of strings is expected: %v", headers, err)
+	} else if param.Retries != nil && *param.Retries < 0 {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("'%s' parameter cannot be negative", retries)
+	} else if !helpers.IsStringPtrNilOrEmtpy(param.RetryBaseDelay) && !isValidDuration(*param.RetryBaseDelay) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Invalid duration in '%s' parameter: %s", retryBaseDelay, *param.RetryBaseDelay)
+	} else if !helpers.IsStringPtrNilOrEmtpy(param.RetryMaxDelay) && !isValidDuration(*param.RetryMaxDelay) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Invalid duration in '%s' parameter: %s", retryMaxDelay, *param.RetryMaxDelay)
+	} else if !helpers.IsStringPtrNilOrEmtpy(param.RequestTimeout) && !isValidDuration(*param.RequestTimeout) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Invalid duration in '%s' parameter: %s", requestTimeout, *param.RequestTimeout)
+	} else if !helpers.IsStringPtrNilOrEmtpy(param.Timeout) && !isValidDuration(*param.Timeout) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Invalid duration in '%s' parameter: %s", timeout, *param.Timeout)
+	} else if param.Parallelism != nil && *param.Parallelism < 1 {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("'%s' parameter must be at least 1", parallelism)
+	} else if m := helpers.StringPtrValue(param.CassetteMode); !helpers.IsStringPtrNilOrEmtpy(param.Cassette) && m != CassetteRecord && m != CassetteReplay {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Unsupported '%s' parameter: %s", cassetteMode, m)
+	} else if !param.IsAPIVersion(APIVersion2) && !param.IsAPIVersion(APIVersion3) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Unsupported '%s' parameter: %s", apiVersion, *param.APIVersion)
+	} else if !helpers.IsStringPtrNilOrEmtpy(param.CommentFormat) && *param.CommentFormat != FormatPlain && *param.CommentFormat != FormatMarkdown {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Unsupported '%s' parameter: %s", commentFormat, *param.CommentFormat)
 	}
 
-	auth.Username = *param.Username
-	auth.Password = *param.Password
-
 	if param.Meta.mandatoryPresent && param.Meta.valid {
 		// Next the initialized context needs to be validated against the input parameters
 		// At this point we know we have a valid:
@@ -222,14 +541,29 @@ func (param *JiraAPIResourceParameters) validate() {
 					customFieldValueAsIs,
 					customFieldValueFromFile)
 			}
-		case ReadStatus:
+		case ReadStatus, ReadChangelog:
 			if helpers.IsStringPtrNilOrEmtpy(param.Destination) {
 				// This context requires a destination to store the ouput on concourse
 				param.Meta.valid = false
 				param.Meta.Msg = fmt.Sprintf("Missing destination")
 			}
 		case AddComment:
-			fallthrough
+			param.validateAddComment()
+		case PruneComments:
+			param.validatePruneComments()
+		case ReadComments:
+			param.validateReadComments()
+		case SetIssueProperty, ReadIssueProperty:
+			param.validateIssueProperty()
+		case BulkCreate:
+			if helpers.IsStringPtrNilOrEmtpy(param.BulkCreate.Manifest) {
+				param.Meta.valid = false
+				param.Meta.Msg = fmt.Sprintf("Missing '%s' parameter", manifest)
+			} else if helpers.IsStringPtrNilOrEmtpy(param.Destination) {
+				// The keys of the created issues are written in the destination
+				param.Meta.valid = false
+				param.Meta.Msg = "Missing destination"
+			}
 		case ReadIssue:
 			fallthrough
 		default:
@@ -238,6 +572,166 @@ func (param *JiraAPIResourceParameters) validate() {
 	}
 }
 
+// Returns true if the credentials required by the authentication type are present: a username and a password for
+// the basic authentication, a token for the bearer authentication.
+func (param *JiraAPIResourceParameters) credentialsPresent() bool {
+	switch helpers.StringPtrValue(param.AuthType) {
+	case auth.TypeBearer:
+		return !helpers.IsStringPtrNilOrEmtpy(param.Token)
+	case auth.TypeOAuth1:
+		return !helpers.IsStringPtrNilOrEmtpy(param.ConsumerKey) && !helpers.IsStringPtrNilOrEmtpy(param.PrivateKey) &&
+			!helpers.IsStringPtrNilOrEmtpy(param.AccessToken)
+	case auth.TypeOAuth2:
+		return !helpers.IsStringPtrNilOrEmtpy(param.ClientId) && !helpers.IsStringPtrNilOrEmtpy(param.ClientSecret)
+	default:
+		return !helpers.IsStringPtrNilOrEmtpy(param.Username) && !helpers.IsStringPtrNilOrEmtpy(param.Password)
+	}
+}
+
+func isAuthType(t string) bool {
+	return t == auth.TypeBasic || t == auth.TypeBearer || t == auth.TypeOAuth1 || t == auth.TypeOAuth2
+}
+
+// Checks that the private key is a valid RSA key when the oauth1 authentication is used
+func (param *JiraAPIResourceParameters) validateOAuth1() bool {
+	if helpers.StringPtrValue(param.AuthType) != auth.TypeOAuth1 {
+		return true
+	}
+
+	if _, err := auth.ParsePrivateKey(*param.PrivateKey); err != nil {
+		param.Meta.Msg = fmt.Sprintf("Invalid '%s' parameter: %v", privateKey, err)
+		return false
+	}
+
+	return true
+}
+
+// Returns the extra headers sent with every request: the ones of the 'headers' JSON object and the User-Agent
+func (param *JiraAPIResourceParameters) ExtraHeaders() (map[string]string, error) {
+	extra := make(map[string]string)
+
+	if !helpers.IsStringPtrNilOrEmtpy(param.Headers) {
+		if err := json.Unmarshal([]byte(*param.Headers), &extra); err != nil {
+			return nil, err
+		}
+	}
+
+	if !helpers.IsStringPtrNilOrEmtpy(param.UserAgent) {
+		extra["User-Agent"] = *param.UserAgent
+	}
+
+	return extra, nil
+}
+
+// Returns true if the resource targets the specified version of the Jira REST API. An unspecified version is
+// considered to be the version 2.
+func (param *JiraAPIResourceParameters) IsAPIVersion(version string) bool {
+	if helpers.IsStringPtrNilOrEmtpy(param.APIVersion) {
+		return version == APIVersion2
+	}
+
+	return *param.APIVersion == version
+}
+
+func (param *JiraAPIResourceParameters) validateAddComment() {
+	visibilityType := param.AddComment.CommentVisibilityType
+	serviceDesk := param.AddComment.CommentServiceDesk
+
+	if !helpers.IsStringPtrNilOrEmtpy(visibilityType) {
+		if *visibilityType != VisibilityRole && *visibilityType != VisibilityGroup {
+			param.Meta.valid = false
+			param.Meta.Msg = fmt.Sprintf("Invalid '%s' parameter: %s", commentVisibilityType, *visibilityType)
+		} else if helpers.IsStringPtrNilOrEmtpy(param.AddComment.CommentVisibilityValue) {
+			param.Meta.valid = false
+			param.Meta.Msg = fmt.Sprintf("'%s' parameter was specified yet the '%s' parameter was not", commentVisibilityType, commentVisibilityValue)
+		}
+	}
+
+	if !helpers.IsStringPtrNilOrEmtpy(serviceDesk) {
+		if *serviceDesk != ServiceDeskInternal && *serviceDesk != ServiceDeskPublic {
+			param.Meta.valid = false
+			param.Meta.Msg = fmt.Sprintf("Invalid '%s' parameter: %s", commentServiceDesk, *serviceDesk)
+		} else if !helpers.IsStringPtrNilOrEmtpy(visibilityType) {
+			// The service desk API has no notion of role or group visibility
+			param.Meta.valid = false
+			param.Meta.Msg = fmt.Sprintf("'%s' and '%s' parameters cannot be used together", commentServiceDesk, commentVisibilityType)
+		}
+	}
+}
+
+func (param *JiraAPIResourceParameters) validateReadComments() {
+	read := param.ReadComments
+
+	if helpers.IsStringPtrNilOrEmtpy(param.Destination) {
+		// This context requires a destination to store the ouput on concourse
+		param.Meta.valid = false
+		param.Meta.Msg = "Missing destination"
+	} else if !helpers.IsStringPtrNilOrEmtpy(read.Match) && !isValidRegexp(*read.Match) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Invalid regular expression in '%s' parameter", commentMatch)
+	} else if !helpers.IsStringPtrNilOrEmtpy(read.Since) {
+		if _, err := helpers.ParseTimeOrAge(*read.Since, time.Now()); err != nil {
+			param.Meta.valid = false
+			param.Meta.Msg = fmt.Sprintf("Invalid date in '%s' parameter: %s", commentSince, *read.Since)
+		}
+	}
+}
+
+func (param *JiraAPIResourceParameters) validateIssueProperty() {
+	property := param.IssueProperty
+	value := helpers.StringPtrValue(property.PropertyValue)
+
+	if helpers.IsStringPtrNilOrEmtpy(property.PropertyKey) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Missing '%s' parameter", propertyKey)
+	} else if param.Context == ReadIssueProperty && helpers.IsStringPtrNilOrEmtpy(param.Destination) {
+		// This context requires a destination to store the ouput on concourse
+		param.Meta.valid = false
+		param.Meta.Msg = "Missing destination"
+	} else if param.Context == SetIssueProperty && value == "" && helpers.IsStringPtrNilOrEmtpy(property.PropertyValueFromFile) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Missing 'property value' parameter (%s or %s)", propertyValue, propertyValueFromFile)
+	} else if param.Context == SetIssueProperty && value != "" && !json.Valid([]byte(value)) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("The '%s' parameter is not valid JSON", propertyValue)
+	}
+}
+
+func (param *JiraAPIResourceParameters) validatePruneComments() {
+	prune := param.PruneComments
+
+	if helpers.IsStringPtrNilOrEmtpy(prune.Match) && helpers.IsStringPtrNilOrEmtpy(param.AddComment.CommentUpsertKey) {
+		// Without any of those, every comment of the resource's user would be pruned
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Missing '%s' or '%s' parameter", pruneMatch, commentUpsertKey)
+	} else if !helpers.IsStringPtrNilOrEmtpy(prune.Match) && !isValidRegexp(*prune.Match) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Invalid regular expression in '%s' parameter", pruneMatch)
+	} else if !helpers.IsStringPtrNilOrEmtpy(prune.OlderThan) && !isValidDuration(*prune.OlderThan) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Invalid duration in '%s' parameter: %s", pruneOlderThan, *prune.OlderThan)
+	} else if prune.KeepLast != nil && *prune.KeepLast < 0 {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("'%s' parameter cannot be negative", pruneKeepLast)
+	} else if helpers.StringPtrValue(prune.Action) != PruneDelete && helpers.StringPtrValue(prune.Action) != PruneEdit {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("Invalid '%s' parameter: %s", pruneAction, helpers.StringPtrValue(prune.Action))
+	} else if *prune.Action == PruneEdit && helpers.IsStringPtrNilOrEmtpy(param.AddComment.CommentBody) {
+		param.Meta.valid = false
+		param.Meta.Msg = fmt.Sprintf("'%s' parameter is required to edit the pruned comments", commentBody)
+	}
+}
+
+func isValidRegexp(expr string) bool {
+	_, err := regexp.Compile(expr)
+	return err == nil
+}
+
+func isValidDuration(value string) bool {
+	_, err := helpers.ParseDuration(value)
+	return err == nil
+}
+
 func (param *JiraAPIResourceParameters) initializeContext(contextString *string) {
 	if contextString == nil {
 		param.Context = Unknown
@@ -126,6 +126,117 @@ func TestPostParse_Validate(t *testing.T) {
 		assert.False(t, param.Meta.Ready(), "method Ready() returned true")
 	})
 
+	t.Run("app parameters VALID AND READY from VALID inputs (BEARER TOKEN WITHOUT USERNAME NOR PASSWORD)", func(t *testing.T) {
+		// Arrange
+		param = convertToJiraApiResourceParameters(param)
+		*param.Username = ""
+		*param.Password = ""
+		*param.AuthType = "bearer"
+		*param.Token = tParam.Password
+		context = "ReadIssue"
+		issueList = "ABC-123 DEF-456"
+
+		// Act
+		param.InitializeAndValidatePostParse(&context, &issueList)
+
+		// Assert
+		assert.True(t, param.Meta.AllMandatoryValuesPresent(), "method AllMandatoryValuesPresent() returned false")
+		assert.True(t, param.Meta.Ready(), "method Ready() returned false")
+	})
+
+	t.Run("app parameters NOT VALID NOR READY from INVALID inputs (BEARER WITHOUT TOKEN)", func(t *testing.T) {
+		// Arrange
+		param = convertToJiraApiResourceParameters(param)
+		*param.AuthType = "bearer"
+		context = "ReadIssue"
+		issueList = "ABC-123 DEF-456"
+
+		// Act
+		param.InitializeAndValidatePostParse(&context, &issueList)
+
+		// Assert
+		assert.False(t, param.Meta.AllMandatoryValuesPresent(), "method AllMandatoryValuesPresent() returned true")
+		assert.False(t, param.Meta.Ready(), "method Ready() returned true")
+	})
+
+	t.Run("app parameters NOT VALID NOR READY from INVALID inputs (UNKNOWN AUTH TYPE)", func(t *testing.T) {
+		// Arrange
+		param = convertToJiraApiResourceParameters(param)
+		*param.AuthType = "digest"
+		context = "ReadIssue"
+		issueList = "ABC-123 DEF-456"
+
+		// Act
+		param.InitializeAndValidatePostParse(&context, &issueList)
+
+		// Assert
+		assert.False(t, param.Meta.Ready(), "method Ready() returned true")
+	})
+
+	t.Run("app parameters NOT VALID NOR READY from INVALID inputs (OAUTH1 WITH INVALID PRIVATE KEY)", func(t *testing.T) {
+		// Arrange
+		param = convertToJiraApiResourceParameters(param)
+		*param.AuthType = "oauth1"
+		*param.ConsumerKey = "jira-api-resource"
+		*param.PrivateKey = "not a PEM key"
+		*param.AccessToken = tParam.Password
+		context = "ReadIssue"
+		issueList = "ABC-123 DEF-456"
+
+		// Act
+		param.InitializeAndValidatePostParse(&context, &issueList)
+
+		// Assert
+		assert.True(t, param.Meta.AllMandatoryValuesPresent(), "method AllMandatoryValuesPresent() returned false")
+		assert.False(t, param.Meta.Ready(), "method Ready() returned true")
+	})
+
+	t.Run("app parameters NOT VALID NOR READY from INVALID inputs (OAUTH2 WITHOUT CLIENT SECRET)", func(t *testing.T) {
+		// Arrange
+		param = convertToJiraApiResourceParameters(param)
+		*param.AuthType = "oauth2"
+		*param.ClientId = tParam.Username
+		context = "ReadIssue"
+		issueList = "ABC-123 DEF-456"
+
+		// Act
+		param.InitializeAndValidatePostParse(&context, &issueList)
+
+		// Assert
+		assert.False(t, param.Meta.AllMandatoryValuesPresent(), "method AllMandatoryValuesPresent() returned true")
+		assert.False(t, param.Meta.Ready(), "method Ready() returned true")
+	})
+
+	t.Run("app parameters NOT VALID NOR READY from INVALID inputs (CLIENT CERTIFICATE WITHOUT KEY)", func(t *testing.T) {
+		// Arrange
+		param = convertToJiraApiResourceParameters(param)
+		*param.ClientCert = "-----BEGIN CERTIFICATE-----"
+		context = "ReadIssue"
+		issueList = "ABC-123 DEF-456"
+
+		// Act
+		param.InitializeAndValidatePostParse(&context, &issueList)
+
+		// Assert
+		assert.True(t, param.Meta.AllMandatoryValuesPresent(), "method AllMandatoryValuesPresent() returned false")
+		assert.False(t, param.Meta.Ready(), "method Ready() returned true")
+	})
+
+	t.Run("app parameters NOT VALID NOR READY from INVALID inputs (HEADERS NOT A JSON OBJECT)", func(t *testing.T) {
+		// Arrange
+		param = convertToJiraApiResourceParameters(param)
+		*param.Headers = `["X-Api-Key"]`
+		context = "ReadIssue"
+		issueList = "ABC-123 DEF-456"
+
+		// Act
+		param.InitializeAndValidatePostParse(&context, &issueList)
+
+		// Assert
+		assert.True(t, param.Meta.AllMandatoryValuesPresent(), "method AllMandatoryValuesPresent() returned false")
+		assert.False(t, param.Meta.Ready(), "method Ready() returned true")
+	})
+
 	t.Run("app parameters NOT VALID NOR READY from INVALID inputs (EMPTY ISSUES)", func(t *testing.T) {
 		// Arrange
 		param = convertToJiraApiResourceParameters(param)
@@ -141,12 +252,100 @@ func TestPostParse_Validate(t *testing.T) {
 		assert.False(t, param.Meta.Ready(), "method Ready() returned true")
 	})
 
+	t.Run("app parameters NOT READY from INVALID inputs (UNKNOWN COMMENT VISIBILITY)", func(t *testing.T) {
+		// Arrange
+		param = convertToJiraApiResourceParameters(param)
+		*param.AddComment.CommentVisibilityType = "everyone"
+		*param.AddComment.CommentVisibilityValue = "Developers"
+		context = "AddComment"
+		issueList = "ABC-123 DEF-456"
+
+		// Act
+		param.InitializeAndValidatePostParse(&context, &issueList)
+
+		// Assert
+		assert.True(t, param.Meta.AllMandatoryValuesPresent(), "method AllMandatoryValuesPresent() returned false")
+		assert.False(t, param.Meta.Ready(), "method Ready() returned true")
+	})
+
+	t.Run("app parameters NOT READY from INVALID inputs (VISIBILITY WITH SERVICE DESK)", func(t *testing.T) {
+		// Arrange
+		param = convertToJiraApiResourceParameters(param)
+		*param.AddComment.CommentVisibilityType = configuration.VisibilityRole
+		*param.AddComment.CommentVisibilityValue = "Developers"
+		*param.AddComment.CommentServiceDesk = configuration.ServiceDeskInternal
+		context = "AddComment"
+		issueList = "ABC-123 DEF-456"
+
+		// Act
+		param.InitializeAndValidatePostParse(&context, &issueList)
+
+		// Assert
+		assert.True(t, param.Meta.AllMandatoryValuesPresent(), "method AllMandatoryValuesPresent() returned false")
+		assert.False(t, param.Meta.Ready(), "method Ready() returned true")
+	})
+
+	t.Run("app parameters VALID AND READY from VALID inputs (INTERNAL SERVICE DESK COMMENT)", func(t *testing.T) {
+		// Arrange
+		param = convertToJiraApiResourceParameters(param)
+		*param.AddComment.CommentServiceDesk = configuration.ServiceDeskInternal
+		context = "AddComment"
+		issueList = "ABC-123 DEF-456"
+
+		// Act
+		param.InitializeAndValidatePostParse(&context, &issueList)
+
+		// Assert
+		assert.True(t, param.Meta.AllMandatoryValuesPresent(), "method AllMandatoryValuesPresent() returned false")
+		assert.True(t, param.Meta.Ready(), "method Ready() returned false")
+	})
+
+	t.Run("app parameters VALID AND READY from VALID inputs (BULK CREATE WITHOUT ISSUES)", func(t *testing.T) {
+		// Arrange
+		param = convertToJiraApiResourceParameters(param)
+		param.IssueList = nil
+		*param.BulkCreate.Manifest = "checklist.yml"
+		context = "BulkCreate"
+		issueList = ""
+
+		// Act
+		param.InitializeAndValidatePostParse(&context, &issueList)
+
+		// Assert
+		assert.True(t, param.Meta.AllMandatoryValuesPresent(), "method AllMandatoryValuesPresent() returned false")
+		assert.True(t, param.Meta.Ready(), "method Ready() returned false")
+	})
+
+	t.Run("app parameters NOT READY from INVALID inputs (BULK CREATE WITHOUT MANIFEST)", func(t *testing.T) {
+		// Arrange
+		param = convertToJiraApiResourceParameters(param)
+		param.IssueList = nil
+		context = "BulkCreate"
+		issueList = ""
+
+		// Act
+		param.InitializeAndValidatePostParse(&context, &issueList)
+
+		// Assert
+		assert.True(t, param.Meta.AllMandatoryValuesPresent(), "method AllMandatoryValuesPresent() returned false")
+		assert.False(t, param.Meta.Ready(), "method Ready() returned true")
+	})
 }
 
 func convertToJiraApiResourceParameters(param configuration.JiraAPIResourceParameters) configuration.JiraAPIResourceParameters {
 	*param.JiraAPIUrl = tParam.JiraAPIUrl
 	*param.Username = tParam.Username
 	*param.Password = tParam.Password
+	*param.AuthType = ""
+	*param.Token = ""
+	*param.ConsumerKey = ""
+	*param.PrivateKey = ""
+	*param.AccessToken = ""
+	*param.ClientId = ""
+	*param.ClientSecret = ""
+	*param.ClientCert = ""
+	*param.ClientKey = ""
+	*param.Headers = ""
 	*param.Destination = tParam.Destination
 	*param.ClosedStatusName = tParam.ClosedStatusName
 	*param.TransitionName = tParam.TransitionName
@@ -154,6 +353,12 @@ func convertToJiraApiResourceParameters(param configuration.JiraAPIResourceParam
 
 	*param.EditCustomFieldParam.CustomFieldName = tParam.EditCustomFieldParam.CustomFieldName
 
+	*param.AddComment.CommentVisibilityType = ""
+	*param.AddComment.CommentVisibilityValue = ""
+	*param.AddComment.CommentServiceDesk = ""
+
+	*param.BulkCreate.Manifest = ""
+
 	param.Flags.ForceOpen = fPtr
 
 	TestLoggingLevel := "INFO"
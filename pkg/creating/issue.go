@@ -0,0 +1,71 @@
+package creating
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Body of a bulk creation request (POST /issue/bulk)
+type BulkCreateRequest struct {
+	IssueUpdates []IssueUpdate `json:"issueUpdates"`
+}
+
+type IssueUpdate struct {
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// Response of a bulk creation request. Jira creates every issue it can: the created issues are listed in the order
+// of the request, skipping the failed elements which are reported by their index in the request.
+type BulkCreateResponse struct {
+	Issues []CreatedIssue `json:"issues"`
+	Errors []BulkError    `json:"errors"`
+}
+
+type CreatedIssue struct {
+	Id   string `json:"id"`
+	Key  string `json:"key"`
+	Self string `json:"self"`
+}
+
+type BulkError struct {
+	Status              int           `json:"status"`
+	ElementErrors       ElementErrors `json:"elementErrors"`
+	FailedElementNumber int           `json:"failedElementNumber"`
+}
+
+type ElementErrors struct {
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+// The outcome of the creation of one row of the manifest, as written in the result file
+type RowResult struct {
+	Row     int    `json:"row"`
+	Summary string `json:"summary"`
+	Key     string `json:"key,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type BulkCreateOutput struct {
+	Created int         `json:"created"`
+	Failed  int         `json:"failed"`
+	Rows    []RowResult `json:"rows"`
+}
+
+// Returns every error of a failed element as a single message
+func (e ElementErrors) String() string {
+	messages := append([]string{}, e.ErrorMessages...)
+
+	fields := make([]string, 0, len(e.Errors))
+	for f := range e.Errors {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	for _, f := range fields {
+		messages = append(messages, fmt.Sprintf("%s: %s", f, e.Errors[f]))
+	}
+
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,148 @@
+// Package creating provides a Jira API interface service and implementation of Jira's domain object as Go
+// structures in the context of creating issues in bulk.
+package creating
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Columns of a CSV manifest that describe the issue itself, every other column is the value of a field
+const (
+	columnProject     = "project"
+	columnType        = "type"
+	columnSummary     = "summary"
+	columnDescription = "description"
+	columnParent      = "parent"
+)
+
+// A ManifestRow describes one issue to create. The fields are keyed by their id (i.e. 'labels', 'customfield_10010')
+// and sent as is.
+type ManifestRow struct {
+	Project     string                 `yaml:"project"`
+	Type        string                 `yaml:"type"`
+	Summary     string                 `yaml:"summary"`
+	Description string                 `yaml:"description"`
+	Parent      string                 `yaml:"parent"`
+	Fields      map[string]interface{} `yaml:"fields"`
+}
+
+// Reads the issues to create from a YAML (a list of rows) or CSV (a header line naming the columns) manifest. The
+// format is chosen from the extension of the file.
+func ReadManifest(path string) ([]ManifestRow, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return ParseYAMLManifest(b)
+	case ".csv":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return ParseCSVManifest(csv.NewReader(f))
+	default:
+		return nil, fmt.Errorf("unsupported manifest format: %s (expected .yml, .yaml or .csv)", path)
+	}
+}
+
+func ParseYAMLManifest(b []byte) ([]ManifestRow, error) {
+	var rows []ManifestRow
+	if err := yaml.Unmarshal(b, &rows); err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		for k, v := range rows[i].Fields {
+			rows[i].Fields[k] = jsonCompatible(v)
+		}
+	}
+
+	return rows, validateRows(rows)
+}
+
+func ParseCSVManifest(r *csv.Reader) ([]ManifestRow, error) {
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	} else if len(records) == 0 {
+		return nil, errors.New("the manifest has no header line")
+	}
+
+	header := records[0]
+	rows := make([]ManifestRow, 0, len(records)-1)
+
+	for _, record := range records[1:] {
+		row := ManifestRow{Fields: make(map[string]interface{})}
+
+		for i, column := range header {
+			value := strings.TrimSpace(record[i])
+
+			switch c := strings.ToLower(strings.TrimSpace(column)); c {
+			case columnProject:
+				row.Project = value
+			case columnType:
+				row.Type = value
+			case columnSummary:
+				row.Summary = value
+			case columnDescription:
+				row.Description = value
+			case columnParent:
+				row.Parent = value
+			default:
+				// An empty cell means the field is not set on that issue
+				if value != "" {
+					row.Fields[strings.TrimSpace(column)] = value
+				}
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, validateRows(rows)
+}
+
+func validateRows(rows []ManifestRow) error {
+	if len(rows) == 0 {
+		return errors.New("the manifest has no issue to create")
+	}
+
+	for i, row := range rows {
+		if row.Project == "" || row.Type == "" || row.Summary == "" {
+			return fmt.Errorf("row %d of the manifest: the project, the type and the summary are required", i+1)
+		}
+	}
+
+	return nil
+}
+
+// Converts the maps decoded from YAML, whose keys are of any type, into maps that can be encoded in JSON
+func jsonCompatible(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{})
+		for k, e := range value {
+			m[fmt.Sprint(k)] = jsonCompatible(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range value {
+			value[i] = jsonCompatible(e)
+		}
+		return value
+	default:
+		return v
+	}
+}
@@ -0,0 +1,55 @@
+package creating_test
+
+import (
+	"encoding/csv"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/creating"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestParseYAMLManifest(t *testing.T) {
+	rows, err := creating.ParseYAMLManifest([]byte(`
+- project: REL
+  type: Task
+  summary: Tag the release
+  parent: REL-1
+  fields:
+    labels: [release, checklist]
+    customfield_10010:
+      value: High
+- project: REL
+  type: Task
+  summary: Announce the release
+`))
+
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "REL-1", rows[0].Parent)
+	assert.Equal(t, []interface{}{"release", "checklist"}, rows[0].Fields["labels"])
+	assert.Equal(t, map[string]interface{}{"value": "High"}, rows[0].Fields["customfield_10010"])
+	assert.Equal(t, "Announce the release", rows[1].Summary)
+}
+
+func TestParseCSVManifest(t *testing.T) {
+	t.Run("extra columns are fields", func(t *testing.T) {
+		rows, err := creating.ParseCSVManifest(csv.NewReader(strings.NewReader(
+			"project,type,summary,parent,environment\n" +
+				"REL,Sub-task,Tag the release,REL-1,production\n" +
+				"REL,Task,Announce the release,,\n")))
+
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, "Sub-task", rows[0].Type)
+		assert.Equal(t, "REL-1", rows[0].Parent)
+		assert.Equal(t, map[string]interface{}{"environment": "production"}, rows[0].Fields)
+		assert.Empty(t, rows[1].Fields)
+	})
+
+	t.Run("rows without a summary are rejected", func(t *testing.T) {
+		_, err := creating.ParseCSVManifest(csv.NewReader(strings.NewReader("project,type,summary\nREL,Task,\n")))
+
+		assert.EqualError(t, err, "row 1 of the manifest: the project, the type and the summary are required")
+	})
+}
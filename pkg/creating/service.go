@@ -0,0 +1,258 @@
+package creating
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/markup"
+	resulthelper "github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/result"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"net/http"
+)
+
+// Maximum number of issues Jira creates in a single bulk request
+const bulkCreateChunkSize = 50
+
+// Errors written for the rows without an outcome when the creation is aborted (i.e. a failed request, the run being
+// interrupted)
+const (
+	errNoResponse = "no response from Jira, the issue may have been created"
+	errNotSent    = "not sent, the creation was aborted"
+)
+
+// The ServiceBulkCreate struct implements the service.IterativeService and service.ErrorHandlingService interfaces. It
+// creates the issues of a manifest through the bulk API, by chunks of 50, and writes in the destination the key
+// created for each row of the manifest, or the reason why it could not be created. The result file is written before
+// and after each chunk, so that it is complete even when the creation is aborted.
+type ServiceBulkCreate struct {
+	rows        []ManifestRow
+	params      configuration.JiraAPIResourceParameters
+	destination string
+	dryRun      bool
+	chunk       int
+	body        []byte
+	results     []RowResult
+}
+
+// See service/service.go for details
+func (s *ServiceBulkCreate) InitJiraAPI(params configuration.JiraAPIResourceParameters) (rest.JiraAPI, error) {
+	if s.rows == nil {
+		rows, err := ReadManifest(helpers.StringPtrValue(params.BulkCreate.Manifest))
+		if err != nil {
+			return rest.JiraAPI{}, err
+		}
+
+		s.rows = rows
+	}
+	s.params = params
+	s.destination = helpers.StringPtrValue(params.Destination)
+	s.dryRun = helpers.IsBoolPtrTrue(params.Flags.DryRun)
+
+	// The rows of the chunk about to be sent are written without an outcome, in case no response is received. A chunk
+	// whose request can't be built is never sent.
+	start, end := s.chunkBounds()
+	body, bodyErr := s.chunkBody(start, end)
+	if bodyErr != nil {
+		end = start
+	}
+
+	if _, err := s.writeOutput(end); err != nil {
+		return rest.JiraAPI{}, err
+	}
+
+	if bodyErr != nil {
+		return rest.JiraAPI{}, fmt.Errorf("failed to build the request of chunk %d of the manifest: %v", s.chunk+1, bodyErr)
+	}
+	s.body = body
+
+	// A bulk creation can't tell which issues a failed attempt created, so it is never retried
+	return service.PreInitJiraAPI(s, params, http.MethodPost)
+}
+
+// See service/service.go for details
+func (s *ServiceBulkCreate) Reset() {
+	s.rows = nil
+	s.chunk = 0
+	s.results = nil
+}
+
+// See service/service.go for details
+func (s *ServiceBulkCreate) HasNext() bool {
+	// The manifest is read by the first call
+	return s.rows == nil || s.chunk*bulkCreateChunkSize < len(s.rows)
+}
+
+// See service/service.go for details
+func (s *ServiceBulkCreate) GetResults() map[string]string {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServiceBulkCreate) SetResultsFromPrevious(result map[string]string) {
+}
+
+// See service/service.go for details
+func (s *ServiceBulkCreate) GetEndpoint(url string) string {
+	return fmt.Sprintf("%s/issue/bulk", url)
+}
+
+// See service/service.go for details
+func (s *ServiceBulkCreate) CreateRequestBody() []byte {
+	return s.body
+}
+
+// See service/service.go for details
+func (s *ServiceBulkCreate) JSONResponseObject() interface{} {
+	return &BulkCreateResponse{}
+}
+
+// See service/service.go for details
+func (s *ServiceBulkCreate) PostAPICall(result interface{}) error {
+	response, ok := result.(*BulkCreateResponse)
+	if !ok {
+		return errors.New("failed to convert result of type interface{} to response of type creating.BulkCreateResponse")
+	}
+
+	start, end := s.chunkBounds()
+
+	failed := make(map[int]string)
+	for _, e := range response.Errors {
+		failed[e.FailedElementNumber] = e.ElementErrors.String()
+	}
+
+	// The created issues are in the order of the request, the failed elements being skipped
+	created := 0
+	for i := 0; i < end-start; i++ {
+		r := RowResult{Row: start + i + 1, Summary: s.rows[start+i].Summary}
+
+		if msg, isFailed := failed[i]; isFailed {
+			r.Error = msg
+		} else if created < len(response.Issues) {
+			r.Key = response.Issues[created].Key
+			created++
+		} else if !s.dryRun {
+			// Unless the request was not sent (dry run), every row is either created or failed
+			r.Error = "not reported by Jira"
+		}
+
+		s.results = append(s.results, r)
+	}
+
+	log.Logger.Infof("Created %d of %d issue(s) of chunk %d", created, end-start, s.chunk+1)
+	s.chunk++
+
+	_, err := s.writeOutput(len(s.results))
+	return err
+}
+
+// See service/service.go for details. Jira answers a chunk whose every element failed with a 400 and the usual body of
+// a bulk creation, so its errors are recorded like the ones of a partially created chunk.
+func (s *ServiceBulkCreate) HandleAPIError(err *rest.HTTPError) error {
+	var response BulkCreateResponse
+	if err.StatusCode != http.StatusBadRequest || json.Unmarshal([]byte(err.Body), &response) != nil || len(response.Errors) == 0 {
+		return err
+	}
+
+	return s.PostAPICall(&response)
+}
+
+func (s *ServiceBulkCreate) Name() string {
+	return "ServiceBulkCreate"
+}
+
+func (s *ServiceBulkCreate) ExecuteAsLastStep(params configuration.JiraAPIResourceParameters) error {
+	output, err := s.writeOutput(len(s.results))
+	if err != nil {
+		return err
+	}
+
+	for _, r := range output.Rows {
+		if r.Error != "" {
+			log.Logger.Errorf("Row %d of the manifest (%s) could not be created: %s", r.Row, r.Summary, r.Error)
+		}
+	}
+
+	if output.Failed > 0 {
+		return fmt.Errorf("%d of %d issue(s) of the manifest could not be created", output.Failed, len(output.Rows))
+	}
+
+	return nil
+}
+
+// Writes the outcome of every row of the manifest in the result file. The rows sent without a response yet (up to the
+// sent index) and the ones never sent are written as not created.
+func (s *ServiceBulkCreate) writeOutput(sent int) (BulkCreateOutput, error) {
+	output := BulkCreateOutput{Rows: append([]RowResult{}, s.results...)}
+	for i := len(s.results); i < len(s.rows); i++ {
+		r := RowResult{Row: i + 1, Summary: s.rows[i].Summary, Error: errNotSent}
+		if i < sent {
+			r.Error = errNoResponse
+		}
+
+		output.Rows = append(output.Rows, r)
+	}
+
+	for _, r := range output.Rows {
+		if r.Key != "" {
+			output.Created++
+		} else if r.Error != "" {
+			output.Failed++
+		}
+	}
+
+	file, err := resulthelper.CreateDestination(s.destination+"_bulk_create", "json")
+	if err != nil {
+		return output, errors.New("failed to create destination file")
+	}
+	defer file.Close()
+
+	return output, json.NewEncoder(file).Encode(output)
+}
+
+// Returns the fields of the issue of a row. The description is converted like any rich text.
+func (s *ServiceBulkCreate) fields(row ManifestRow) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for k, v := range row.Fields {
+		fields[k] = v
+	}
+
+	fields["project"] = map[string]string{"key": row.Project}
+	fields["issuetype"] = map[string]string{"name": row.Type}
+	fields["summary"] = row.Summary
+
+	if row.Description != "" {
+		fields["description"] = markup.NewRichText(row.Description, s.params)
+	}
+
+	if row.Parent != "" {
+		fields["parent"] = map[string]string{"key": row.Parent}
+	}
+
+	return fields
+}
+
+// Returns the body of the bulk request creating the rows between 'start' and 'end'. It is built when initializing the
+// API so that a value of the manifest that can't be sent as JSON (i.e. a '.nan' in YAML) aborts the creation.
+func (s *ServiceBulkCreate) chunkBody(start, end int) ([]byte, error) {
+	request := BulkCreateRequest{IssueUpdates: make([]IssueUpdate, 0, end-start)}
+
+	for _, row := range s.rows[start:end] {
+		request.IssueUpdates = append(request.IssueUpdates, IssueUpdate{Fields: s.fields(row)})
+	}
+
+	return json.Marshal(request)
+}
+
+func (s *ServiceBulkCreate) chunkBounds() (int, int) {
+	start := s.chunk * bulkCreateChunkSize
+	end := start + bulkCreateChunkSize
+	if end > len(s.rows) {
+		end = len(s.rows)
+	}
+
+	return start, end
+}
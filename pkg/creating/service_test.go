@@ -0,0 +1,205 @@
+package creating_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/creating"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Creates every issue of the bulk requests, except the ones whose summary starts with 'INVALID'. Like Jira, a request
+// whose every element failed is answered with a 400. A request with a summary starting with 'UNAVAILABLE' is answered
+// with a 503.
+func newBulkServer(chunks *[]int) *httptest.Server {
+	created := 0
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request creating.BulkCreateRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		*chunks = append(*chunks, len(request.IssueUpdates))
+
+		response := creating.BulkCreateResponse{}
+		for i, u := range request.IssueUpdates {
+			summary := u.Fields["summary"].(string)
+			if strings.HasPrefix(summary, "UNAVAILABLE") {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			} else if strings.HasPrefix(summary, "INVALID") {
+				response.Errors = append(response.Errors, creating.BulkError{
+					Status:              http.StatusBadRequest,
+					FailedElementNumber: i,
+					ElementErrors:       creating.ElementErrors{Errors: map[string]string{"summary": "invalid summary"}},
+				})
+			} else {
+				created++
+				response.Issues = append(response.Issues, creating.CreatedIssue{Key: fmt.Sprintf("REL-%d", created)})
+			}
+		}
+
+		status := http.StatusCreated
+		if len(response.Issues) == 0 {
+			status = http.StatusBadRequest
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+}
+
+// Writes a CSV manifest of tasks in the directory, the summary of each row (starting at 1) being given by the function
+func writeManifest(t *testing.T, dir string, rows int, summary func(row int) string) string {
+	csvManifest := "project,type,summary\n"
+	for i := 1; i <= rows; i++ {
+		csvManifest += "REL,Task," + summary(i) + "\n"
+	}
+
+	manifest := filepath.Join(dir, "manifest.csv")
+	require.NoError(t, ioutil.WriteFile(manifest, []byte(csvManifest), 0644))
+	return manifest
+}
+
+// Creates the issues of the manifest and returns the error of the creation along with the result file
+func bulkCreate(t *testing.T, dir, manifest string, chunks *[]int) (creating.BulkCreateOutput, error) {
+	server := newBulkServer(chunks)
+	defer server.Close()
+
+	destination := filepath.Join(dir, "out")
+	params := configuration.JiraAPIResourceParameters{Destination: &destination, Context: configuration.BulkCreate}
+	params.BulkCreate.Manifest = &manifest
+
+	client := &rest.Client{BaseURL: server.URL, HTTPClient: server.Client()}
+	err := service.Execute(context.Background(), client, &creating.ServiceBulkCreate{}, params, true)
+
+	b, readErr := ioutil.ReadFile(destination + "_bulk_create.json")
+	require.NoError(t, readErr)
+
+	var output creating.BulkCreateOutput
+	require.NoError(t, json.Unmarshal(b, &output))
+	return output, err
+}
+
+func TestServiceBulkCreate(t *testing.T) {
+	t.Run("the invalid rows are reported along with the keys of the created ones", func(t *testing.T) {
+		// Arrange
+		dir, err := ioutil.TempDir("", "bulk")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		manifest := writeManifest(t, dir, 60, func(row int) string {
+			if row == 2 || row == 55 {
+				return fmt.Sprintf("INVALID Task %d", row)
+			}
+			return fmt.Sprintf("Task %d", row)
+		})
+		var chunks []int
+
+		// Act
+		output, err := bulkCreate(t, dir, manifest, &chunks)
+
+		// Assert
+		assert.EqualError(t, err, "2 of 60 issue(s) of the manifest could not be created")
+		assert.Equal(t, []int{50, 10}, chunks)
+		assert.Equal(t, 58, output.Created)
+		assert.Equal(t, 2, output.Failed)
+		assert.Equal(t, creating.RowResult{Row: 1, Summary: "Task 1", Key: "REL-1"}, output.Rows[0])
+		assert.Equal(t, creating.RowResult{Row: 2, Summary: "INVALID Task 2", Error: "summary: invalid summary"}, output.Rows[1])
+		assert.Equal(t, creating.RowResult{Row: 3, Summary: "Task 3", Key: "REL-2"}, output.Rows[2])
+		assert.Equal(t, "INVALID Task 55", output.Rows[54].Summary)
+		assert.NotEmpty(t, output.Rows[54].Error)
+		assert.Equal(t, "REL-58", output.Rows[59].Key)
+	})
+
+	t.Run("a chunk whose every row is invalid doesn't abort the creation of the next ones", func(t *testing.T) {
+		// Arrange
+		dir, err := ioutil.TempDir("", "bulk")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		manifest := writeManifest(t, dir, 60, func(row int) string {
+			if row <= 50 {
+				return fmt.Sprintf("INVALID Task %d", row)
+			}
+			return fmt.Sprintf("Task %d", row)
+		})
+		var chunks []int
+
+		// Act
+		output, err := bulkCreate(t, dir, manifest, &chunks)
+
+		// Assert: the first chunk was rejected with a 400, the second one was created
+		assert.EqualError(t, err, "50 of 60 issue(s) of the manifest could not be created")
+		assert.Equal(t, []int{50, 10}, chunks)
+		assert.Equal(t, 10, output.Created)
+		assert.Equal(t, 50, output.Failed)
+		require.Len(t, output.Rows, 60)
+		assert.Equal(t, creating.RowResult{Row: 1, Summary: "INVALID Task 1", Error: "summary: invalid summary"}, output.Rows[0])
+		assert.Equal(t, creating.RowResult{Row: 50, Summary: "INVALID Task 50", Error: "summary: invalid summary"}, output.Rows[49])
+		assert.Equal(t, creating.RowResult{Row: 51, Summary: "Task 51", Key: "REL-1"}, output.Rows[50])
+		assert.Equal(t, creating.RowResult{Row: 60, Summary: "Task 60", Key: "REL-10"}, output.Rows[59])
+	})
+
+	t.Run("the keys created before a failed request are written", func(t *testing.T) {
+		// Arrange
+		dir, err := ioutil.TempDir("", "bulk")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		manifest := writeManifest(t, dir, 110, func(row int) string {
+			if row == 60 {
+				return fmt.Sprintf("UNAVAILABLE Task %d", row)
+			}
+			return fmt.Sprintf("Task %d", row)
+		})
+		var chunks []int
+
+		// Act
+		output, err := bulkCreate(t, dir, manifest, &chunks)
+
+		// Assert: the second chunk failed, the third one was never sent
+		assert.Error(t, err)
+		assert.Equal(t, []int{50, 50}, chunks)
+		assert.Equal(t, 50, output.Created)
+		assert.Equal(t, 60, output.Failed)
+		require.Len(t, output.Rows, 110)
+		assert.Equal(t, creating.RowResult{Row: 50, Summary: "Task 50", Key: "REL-50"}, output.Rows[49])
+		assert.Equal(t, "no response from Jira, the issue may have been created", output.Rows[50].Error)
+		assert.Equal(t, "no response from Jira, the issue may have been created", output.Rows[99].Error)
+		assert.Equal(t, "not sent, the creation was aborted", output.Rows[100].Error)
+	})
+	t.Run("a value that can't be sent as JSON aborts the creation before its chunk is sent", func(t *testing.T) {
+		// Arrange
+		dir, err := ioutil.TempDir("", "bulk")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		yamlManifest := "- {project: REL, type: Task, summary: Task 1}\n" +
+			"- {project: REL, type: Task, summary: Task 2, fields: {customfield_10016: .nan}}\n"
+		manifest := filepath.Join(dir, "manifest.yml")
+		require.NoError(t, ioutil.WriteFile(manifest, []byte(yamlManifest), 0644))
+		var chunks []int
+
+		// Act
+		output, err := bulkCreate(t, dir, manifest, &chunks)
+
+		// Assert
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to build the request of chunk 1 of the manifest")
+		assert.Empty(t, chunks)
+		require.Len(t, output.Rows, 2)
+		assert.Equal(t, "not sent, the creation was aborted", output.Rows[0].Error)
+		assert.Equal(t, "not sent, the creation was aborted", output.Rows[1].Error)
+	})
+}
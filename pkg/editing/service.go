@@ -6,15 +6,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/adf"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/markup"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
 	"io/ioutil"
 	"net/http"
 	"strconv"
 )
 
+// Type of the rich-text fields (description, multi-line text fields). Their value is converted to the Atlassian
+// Document Format when the version 3 of the API is used, or to wiki markup when it is written in Markdown.
+const richTextFieldType = "richtext"
+
 // The ServiceEditCustomField struct implements the service.Service interface. It defines the workflow of editing
 // an existing Jira issue.
 type ServiceEditCustomField struct {
@@ -22,6 +28,7 @@ type ServiceEditCustomField struct {
 	fieldKey   string
 	fieldType  string
 	fieldValue string
+	richValue  adf.RichText
 }
 
 // See service/service.go for details
@@ -35,6 +42,7 @@ func (s *ServiceEditCustomField) InitJiraAPI(params configuration.JiraAPIResourc
 	}
 
 	s.fieldValue = val
+	s.richValue = markup.NewRichText(val, params)
 
 	if s.issueId == "" || s.fieldValue == "" || s.fieldType == "" {
 		return rest.JiraAPI{}, errors.New("missing value(s) for ServiceEditCustomField")
@@ -62,7 +70,9 @@ func (s *ServiceEditCustomField) GetEndpoint(url string) string {
 func (s *ServiceEditCustomField) CreateRequestBody() []byte {
 	i := Issue{}
 
-	if numVal, err := strconv.Atoi(s.fieldValue); err == nil && s.fieldType != "string" {
+	if s.fieldType == richTextFieldType {
+		i.AddField(s.fieldKey, s.richValue)
+	} else if numVal, err := strconv.Atoi(s.fieldValue); err == nil && s.fieldType != "string" {
 		i.AddField(s.fieldKey, numVal)
 	} else {
 		i.AddField(s.fieldKey, s.fieldValue)
@@ -0,0 +1,17 @@
+package helpers
+
+import (
+	"io"
+	"io/ioutil"
+)
+
+// Reads what remains of the body and closes it. A connection only goes back to the pool once its response body was
+// fully read.
+func DrainAndClose(body io.ReadCloser) {
+	if body == nil {
+		return
+	}
+
+	_, _ = io.Copy(ioutil.Discard, body)
+	_ = body.Close()
+}
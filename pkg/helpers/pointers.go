@@ -4,6 +4,14 @@ func IsStringPtrNilOrEmtpy(ptr *string) bool {
 	return ptr == nil || *ptr == ""
 }
 
+func StringPtrValue(ptr *string) string {
+	if ptr == nil {
+		return ""
+	}
+
+	return *ptr
+}
+
 func IsBoolPtrTrue(ptr *bool) bool {
 	return ptr != nil && *ptr == true
 }
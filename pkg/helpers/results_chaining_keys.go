@@ -4,5 +4,8 @@ const (
 	ReadingFieldKey   = "FieldKey"          //
 	ParentIssueKey    = "ParentIssueKey"    //
 	StatusNameKey     = "StatusNameKey"     //
-	IssueForceOpenKey = "IssueForceOpenKey" //
+	CommentIdKey      = "CommentIdKey"      //
+	CommentIdsKey     = "CommentIdsKey"     // Comma-separated list of comment ids
+	CommentMarkersKey = "CommentMarkersKey" // Comma-separated list of the encoded upsert keys of the CommentIdsKey comments
+	MyselfIdKey       = "MyselfIdKey"       // Account id (Cloud) or name (Server) of the connected user
 )
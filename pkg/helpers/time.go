@@ -0,0 +1,50 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout of the dates returned by the Jira API (i.e. 2020-02-18T10:42:07.000+0000)
+const JiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// Parses a date returned by the Jira API. Dates formatted as RFC 3339 are also accepted.
+func ParseJiraTime(value string) (time.Time, error) {
+	t, err := time.Parse(JiraTimeLayout, value)
+	if err != nil {
+		return time.Parse(time.RFC3339, value)
+	}
+
+	return t, nil
+}
+
+// Parses a duration in the format accepted by time.ParseDuration. The 'd' (days) and 'w' (weeks) units are also
+// accepted on their own, i.e. '30d' or '2w'.
+func ParseDuration(value string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+
+	for suffix, unit := range units {
+		if strings.HasSuffix(value, suffix) {
+			if count, err := strconv.Atoi(strings.TrimSuffix(value, suffix)); err == nil {
+				return time.Duration(count) * unit, nil
+			}
+		}
+	}
+
+	return time.ParseDuration(value)
+}
+
+// Parses a point in time which is either a date (2020-02-18), a date returned by the Jira API or an RFC 3339 date,
+// or an age relative to 'now' in the format accepted by ParseDuration (i.e. '7d' means seven days before 'now').
+func ParseTimeOrAge(value string, now time.Time) (time.Time, error) {
+	if age, err := ParseDuration(value); err == nil {
+		return now.Add(-age), nil
+	}
+
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	return ParseJiraTime(value)
+}
@@ -0,0 +1,110 @@
+package history
+
+import (
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"sort"
+	"strings"
+	"time"
+)
+
+const statusField = "status"
+
+// Extracts the status transitions from the histories, sorted from the oldest to the most recent one.
+func StatusTransitions(histories []History) []StatusTransition {
+	sorted := make([]History, len(histories))
+	copy(sorted, histories)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		first, _ := helpers.ParseJiraTime(sorted[i].Created)
+		second, _ := helpers.ParseJiraTime(sorted[j].Created)
+		return first.Before(second)
+	})
+
+	transitions := make([]StatusTransition, 0)
+	for _, h := range sorted {
+		for _, item := range h.Items {
+			if item.Field != statusField {
+				continue
+			}
+
+			t := StatusTransition{At: h.Created, From: item.FromString, To: item.ToString}
+			if h.Author != nil {
+				t.Author = h.Author.DisplayName
+			}
+			transitions = append(transitions, t)
+		}
+	}
+
+	return transitions
+}
+
+// Computes the metrics of an issue from its creation date, its current status and its status transitions (sorted
+// from the oldest). The time spent in the current status is counted up to 'now'. The lead time goes from the creation
+// to the last transition into a 'done' status and the cycle time from the first transition into an 'in progress'
+// status to that same transition. Status names are compared regardless of their case.
+func ComputeMetrics(created time.Time, currentStatus string, transitions []StatusTransition, doneStatuses, inProgressStatuses []string, now time.Time) Metrics {
+	m := Metrics{TimeInStatus: make(map[string]int64)}
+
+	status := currentStatus
+	if len(transitions) > 0 {
+		status = transitions[0].From
+	}
+
+	since := created
+	var doneAt, inProgressAt time.Time
+
+	for _, t := range transitions {
+		at, err := helpers.ParseJiraTime(t.At)
+		if err != nil {
+			continue
+		}
+
+		m.TimeInStatus[status] += seconds(at.Sub(since))
+		status, since = t.To, at
+
+		if inProgressAt.IsZero() && contains(inProgressStatuses, t.To) {
+			inProgressAt = at
+		}
+
+		if contains(doneStatuses, t.To) {
+			doneAt = at
+		}
+	}
+
+	m.TimeInStatus[status] += seconds(now.Sub(since))
+
+	if !inProgressAt.IsZero() {
+		m.InProgressAt = inProgressAt.Format(helpers.JiraTimeLayout)
+	}
+
+	// An issue that left its 'done' status (i.e. reopened) isn't considered done anymore
+	if m.Done = contains(doneStatuses, status) && !doneAt.IsZero(); m.Done {
+		m.DoneAt = doneAt.Format(helpers.JiraTimeLayout)
+		lead := seconds(doneAt.Sub(created))
+		m.LeadTimeSeconds = &lead
+
+		if !inProgressAt.IsZero() && !inProgressAt.After(doneAt) {
+			cycle := seconds(doneAt.Sub(inProgressAt))
+			m.CycleTimeSeconds = &cycle
+		}
+	}
+
+	return m
+}
+
+func seconds(d time.Duration) int64 {
+	if d < 0 {
+		return 0
+	}
+
+	return int64(d / time.Second)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package history_test
+
+import (
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var (
+	created            = time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	now                = time.Date(2020, 3, 10, 0, 0, 0, 0, time.UTC)
+	doneStatuses       = []string{"Done", "Closed"}
+	inProgressStatuses = []string{"In Progress"}
+	day                = int64(24 * 60 * 60)
+)
+
+func TestStatusTransitions(t *testing.T) {
+	// Arrange
+	histories := []history.History{
+		{Id: "2", Created: "2020-03-03T00:00:00.000+0000", Items: []history.HistoryItem{{Field: "status", FromString: "In Progress", ToString: "Done"}}},
+		{Id: "1", Created: "2020-03-02T00:00:00.000+0000", Items: []history.HistoryItem{
+			{Field: "assignee", FromString: "", ToString: "bot"},
+			{Field: "status", FromString: "Open", ToString: "In Progress"},
+		}},
+	}
+
+	// Act
+	transitions := history.StatusTransitions(histories)
+
+	// Assert
+	require.Len(t, transitions, 2)
+	assert.Equal(t, "In Progress", transitions[0].To)
+	assert.Equal(t, "Done", transitions[1].To)
+}
+
+func TestComputeMetrics(t *testing.T) {
+	t.Run("done issue has lead and cycle times", func(t *testing.T) {
+		// Arrange
+		transitions := []history.StatusTransition{
+			{At: "2020-03-02T00:00:00.000+0000", From: "Open", To: "In Progress"},
+			{At: "2020-03-04T00:00:00.000+0000", From: "In Progress", To: "Review"},
+			{At: "2020-03-05T00:00:00.000+0000", From: "Review", To: "In Progress"},
+			{At: "2020-03-06T00:00:00.000+0000", From: "In Progress", To: "done"},
+		}
+
+		// Act
+		m := history.ComputeMetrics(created, "Done", transitions, doneStatuses, inProgressStatuses, now)
+
+		// Assert
+		assert.True(t, m.Done)
+		assert.Equal(t, 1*day, m.TimeInStatus["Open"])
+		assert.Equal(t, 3*day, m.TimeInStatus["In Progress"])
+		assert.Equal(t, 1*day, m.TimeInStatus["Review"])
+		assert.Equal(t, 4*day, m.TimeInStatus["done"])
+		require.NotNil(t, m.LeadTimeSeconds)
+		assert.Equal(t, 5*day, *m.LeadTimeSeconds)
+		require.NotNil(t, m.CycleTimeSeconds)
+		assert.Equal(t, 4*day, *m.CycleTimeSeconds)
+	})
+
+	t.Run("reopened issue is not done", func(t *testing.T) {
+		// Arrange
+		transitions := []history.StatusTransition{
+			{At: "2020-03-02T00:00:00.000+0000", From: "Open", To: "Closed"},
+			{At: "2020-03-03T00:00:00.000+0000", From: "Closed", To: "Reopened"},
+		}
+
+		// Act
+		m := history.ComputeMetrics(created, "Reopened", transitions, doneStatuses, inProgressStatuses, now)
+
+		// Assert
+		assert.False(t, m.Done)
+		assert.Nil(t, m.LeadTimeSeconds)
+		assert.Equal(t, 7*day, m.TimeInStatus["Reopened"])
+	})
+
+	t.Run("issue without transition stays in its current status", func(t *testing.T) {
+		// Act
+		m := history.ComputeMetrics(created, "Open", nil, doneStatuses, inProgressStatuses, now)
+
+		// Assert
+		assert.Equal(t, map[string]int64{"Open": 9 * day}, m.TimeInStatus)
+		assert.Nil(t, m.CycleTimeSeconds)
+	})
+}
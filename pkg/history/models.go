@@ -0,0 +1,84 @@
+// Package history provides the services and the Go structures needed to read the changelog of Jira issues and to
+// derive metrics (time in each status, lead time, cycle time) from their status transitions.
+package history
+
+import "github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/user"
+
+// This struct is a representation of an issue read with its changelog (expand=changelog).
+type Issue struct {
+	Key       string    `json:"key"`
+	Fields    Fields    `json:"fields"`
+	Changelog Changelog `json:"changelog"`
+}
+
+type Fields struct {
+	Created string  `json:"created"`
+	Status  *Status `json:"status"`
+}
+
+type Status struct {
+	Name string `json:"name"`
+}
+
+// This struct is the changelog embedded in an issue. It may only hold the first page of histories, the remaining
+// ones are then read through the /issue/{key}/changelog endpoint (Jira Cloud).
+type Changelog struct {
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+	Histories  []History `json:"histories"`
+}
+
+// This struct is a page of histories as returned by the /issue/{key}/changelog endpoint.
+type ChangelogPage struct {
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+	IsLast     bool      `json:"isLast"`
+	Values     []History `json:"values"`
+}
+
+// This struct is a single change of the issue, which can update multiple fields at once.
+type History struct {
+	Id      string        `json:"id"`
+	Author  *user.User    `json:"author"`
+	Created string        `json:"created"`
+	Items   []HistoryItem `json:"items"`
+}
+
+type HistoryItem struct {
+	Field      string `json:"field"`
+	FieldType  string `json:"fieldtype"`
+	From       string `json:"from"`
+	FromString string `json:"fromString"`
+	To         string `json:"to"`
+	ToString   string `json:"toString"`
+}
+
+// This struct is a change of status of the issue as written in the destination.
+type StatusTransition struct {
+	At     string `json:"at"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Author string `json:"author,omitempty"`
+}
+
+// This struct holds the metrics derived from the status transitions. Durations are expressed in seconds. The lead
+// and cycle times are only set once the issue is in one of the 'done' statuses.
+type Metrics struct {
+	TimeInStatus     map[string]int64 `json:"timeInStatusSeconds"`
+	Done             bool             `json:"done"`
+	DoneAt           string           `json:"doneAt,omitempty"`
+	InProgressAt     string           `json:"inProgressAt,omitempty"`
+	LeadTimeSeconds  *int64           `json:"leadTimeSeconds,omitempty"`
+	CycleTimeSeconds *int64           `json:"cycleTimeSeconds,omitempty"`
+}
+
+// This struct is the content of the file written in the destination for each issue.
+type ChangelogOutput struct {
+	Issue         string             `json:"issue"`
+	Created       string             `json:"created"`
+	CurrentStatus string             `json:"currentStatus"`
+	Transitions   []StatusTransition `json:"transitions"`
+	Metrics       Metrics            `json:"metrics"`
+}
@@ -0,0 +1,164 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	resulthelper "github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/result"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const changelogPageSize = 100
+
+// The ServiceReadChangelog struct implements the service.IterativeService interface. The first call reads the issue
+// with its embedded changelog. When the changelog holds more histories than what was embedded (Jira Cloud), the
+// remaining ones are read through the paginated /issue/{key}/changelog endpoint.
+type ServiceReadChangelog struct {
+	issueId     string
+	destination string
+
+	issueRead bool
+	hasNext   bool
+	startAt   int
+	issue     Issue
+	histories []History
+	seen      map[string]bool
+}
+
+// See service/service.go for details
+func (s *ServiceReadChangelog) InitJiraAPI(params configuration.JiraAPIResourceParameters) (rest.JiraAPI, error) {
+	s.issueId = params.ActiveIssue
+	s.destination = helpers.StringPtrValue(params.Destination)
+
+	return service.PreInitJiraAPI(s, params, http.MethodGet)
+}
+
+// See service/service.go for details
+func (s *ServiceReadChangelog) Reset() {
+	s.issueRead = false
+	s.hasNext = true
+	s.startAt = 0
+	s.issue = Issue{}
+	s.histories = make([]History, 0)
+	s.seen = make(map[string]bool)
+}
+
+// See service/service.go for details
+func (s *ServiceReadChangelog) HasNext() bool {
+	return s.hasNext
+}
+
+// See service/service.go for details
+func (s *ServiceReadChangelog) GetResults() map[string]string {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServiceReadChangelog) SetResultsFromPrevious(result map[string]string) {
+}
+
+// See service/service.go for details
+func (s *ServiceReadChangelog) GetEndpoint(url string) string {
+	if !s.issueRead {
+		return fmt.Sprintf("%s/issue/%s?expand=changelog&fields=created,status", url, s.issueId)
+	}
+
+	return fmt.Sprintf("%s/issue/%s/changelog?startAt=%d&maxResults=%d", url, s.issueId, s.startAt, changelogPageSize)
+}
+
+// See service/service.go for details
+func (s *ServiceReadChangelog) CreateRequestBody() []byte {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServiceReadChangelog) JSONResponseObject() interface{} {
+	if !s.issueRead {
+		return &Issue{}
+	}
+
+	return &ChangelogPage{}
+}
+
+// See service/service.go for details
+func (s *ServiceReadChangelog) PostAPICall(result interface{}) error {
+	switch r := result.(type) {
+	case *Issue:
+		s.issue = *r
+		s.issueRead = true
+		s.addHistories(r.Changelog.Histories)
+		s.startAt = r.Changelog.StartAt + len(r.Changelog.Histories)
+		s.hasNext = len(r.Changelog.Histories) > 0 && s.startAt < r.Changelog.Total
+	case *ChangelogPage:
+		s.addHistories(r.Values)
+		s.startAt = r.StartAt + len(r.Values)
+		s.hasNext = !r.IsLast && len(r.Values) > 0 && s.startAt < r.Total
+	default:
+		return errors.New("failed to convert result of type interface{} to changelog of type history.Issue or history.ChangelogPage")
+	}
+
+	return nil
+}
+
+func (s *ServiceReadChangelog) Name() string {
+	return "ServiceReadChangelog"
+}
+
+func (s *ServiceReadChangelog) ExecuteAsLastStep(params configuration.JiraAPIResourceParameters) error {
+	if s.destination == "" {
+		return nil
+	}
+
+	created, err := helpers.ParseJiraTime(s.issue.Fields.Created)
+	if err != nil {
+		return fmt.Errorf("failed to read the creation date of issue %s: %v", s.issueId, err)
+	}
+
+	output := ChangelogOutput{
+		Issue:       s.issue.Key,
+		Created:     s.issue.Fields.Created,
+		Transitions: StatusTransitions(s.histories),
+	}
+
+	if s.issue.Fields.Status != nil {
+		output.CurrentStatus = s.issue.Fields.Status.Name
+	}
+
+	output.Metrics = ComputeMetrics(created, output.CurrentStatus, output.Transitions,
+		splitStatuses(params.ReadChangelog.DoneStatuses), splitStatuses(params.ReadChangelog.InProgressStatuses), time.Now())
+
+	file, err := resulthelper.CreateDestination(s.destination+"_"+s.issueId+"_changelog", "json")
+	if err != nil {
+		return errors.New("failed to create destination file")
+	}
+	defer file.Close()
+
+	return json.NewEncoder(file).Encode(output)
+}
+
+// Histories already read are ignored, the pages of the changelog endpoint may overlap the embedded changelog
+func (s *ServiceReadChangelog) addHistories(histories []History) {
+	for _, h := range histories {
+		if !s.seen[h.Id] {
+			s.seen[h.Id] = true
+			s.histories = append(s.histories, h)
+		}
+	}
+}
+
+func splitStatuses(statuses *string) []string {
+	result := make([]string, 0)
+	for _, status := range strings.Split(helpers.StringPtrValue(statuses), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			result = append(result, status)
+		}
+	}
+
+	return result
+}
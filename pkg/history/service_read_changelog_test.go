@@ -0,0 +1,142 @@
+package history_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/history"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/jiratest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const historiesCount = 250
+
+// Returns 250 histories, one an hour from the creation of the issue, each moving the issue from 'Status i-1' to
+// 'Status i'
+func seedHistories() []jiratest.History {
+	histories := make([]jiratest.History, 0, historiesCount)
+	for i := 1; i <= historiesCount; i++ {
+		histories = append(histories, jiratest.History{
+			Id:      fmt.Sprint(i),
+			Author:  jiratest.DefaultUser(),
+			Created: created.Add(time.Duration(i) * time.Hour).Format("2006-01-02T15:04:05.000-0700"),
+			Items: []jiratest.HistoryItem{{
+				Field:      "status",
+				FieldType:  "jira",
+				FromString: fmt.Sprintf("Status %d", i-1),
+				ToString:   fmt.Sprintf("Status %d", i),
+			}},
+		})
+	}
+
+	return histories
+}
+
+func readChangelog(t *testing.T, server *jiratest.Server, destination string) history.ChangelogOutput {
+	params := configuration.JiraAPIResourceParameters{
+		ActiveIssue: "ABC-1",
+		Context:     configuration.ReadChangelog,
+		Destination: &destination,
+	}
+	done, inProgress := "Done", "In Progress"
+	params.ReadChangelog.DoneStatuses = &done
+	params.ReadChangelog.InProgressStatuses = &inProgress
+
+	client := &rest.Client{BaseURL: server.APIURL(), HTTPClient: server.Client()}
+	require.NoError(t, service.Execute(context.Background(), client, &history.ServiceReadChangelog{}, params, true))
+
+	b, err := ioutil.ReadFile(destination + "_ABC-1_changelog.json")
+	require.NoError(t, err)
+
+	var output history.ChangelogOutput
+	require.NoError(t, json.Unmarshal(b, &output))
+	return output
+}
+
+// Asserts that every history was written once, in order
+func assertEveryTransition(t *testing.T, output history.ChangelogOutput) {
+	require.Len(t, output.Transitions, historiesCount)
+	for i, transition := range output.Transitions {
+		assert.Equal(t, fmt.Sprintf("Status %d", i+1), transition.To)
+	}
+	assert.Equal(t, "Status 250", output.CurrentStatus)
+}
+
+func TestServiceReadChangelog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "changelog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("the histories not embedded in the issue are read page by page", func(t *testing.T) {
+		// Arrange: only the first 100 histories are embedded, as on Jira Cloud
+		server := jiratest.NewServer()
+		defer server.Close()
+		server.SetChangelogLimit(100)
+		server.AddIssue(jiratest.Issue{Key: "ABC-1", Status: "Status 250", Created: created, Histories: seedHistories()})
+
+		// Act
+		output := readChangelog(t, server, filepath.Join(dir, "paged"))
+
+		// Assert: the embedded changelog is followed by the pages starting at 100 and 200
+		assertEveryTransition(t, output)
+		var starts []string
+		for _, r := range server.RequestsTo(http.MethodGet, "/issue/ABC-1/changelog") {
+			starts = append(starts, r.Query.Get("startAt"))
+		}
+		assert.Equal(t, []string{"100", "200"}, starts)
+	})
+
+	t.Run("the histories of a page overlapping the ones already read are not duplicated", func(t *testing.T) {
+		// Arrange: the pages start 10 histories before the requested offset
+		server := jiratest.NewServer()
+		defer server.Close()
+		server.SetChangelogLimit(100)
+		histories := seedHistories()
+		server.AddIssue(jiratest.Issue{Key: "ABC-1", Status: "Status 250", Created: created, Histories: histories})
+		server.OnRequest(func(w http.ResponseWriter, r *http.Request) bool {
+			if !strings.HasSuffix(r.URL.Path, "/changelog") {
+				return false
+			}
+
+			startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+			start, end := startAt-10, startAt+90
+			if end > len(histories) {
+				end = len(histories)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"startAt": start, "maxResults": 100, "total": len(histories), "isLast": end == len(histories),
+				"values": histories[start:end],
+			})
+			return true
+		})
+
+		// Act
+		output := readChangelog(t, server, filepath.Join(dir, "overlap"))
+
+		// Assert
+		assertEveryTransition(t, output)
+	})
+
+	t.Run("a single request is sent when the whole changelog is embedded", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+		server.AddIssue(jiratest.Issue{Key: "ABC-1", Status: "Status 250", Created: created, Histories: seedHistories()})
+
+		output := readChangelog(t, server, filepath.Join(dir, "embedded"))
+
+		assertEveryTransition(t, output)
+		assert.Len(t, server.Requests(), 1)
+	})
+}
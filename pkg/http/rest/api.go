@@ -2,15 +2,14 @@ package rest
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/auth"
-	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
 	"net/http"
+	"time"
 )
 
 type CreateBodyFN func() []byte
@@ -18,7 +17,7 @@ type GetEndpointFN func(string) string
 type JsonObjectFN func() interface{}
 
 type JiraAPIInterface interface {
-	Call() (interface{}, error)
+	Call(ctx context.Context, client *Client) (interface{}, error)
 	processResponse(resp *http.Response) (bool, error)
 }
 
@@ -27,12 +26,18 @@ type JiraAPI struct {
 	Body       []byte
 	JsonObject interface{}
 
-	// 'url' is not exported because at this level it may contains the credentials
-	url string
+	// Allows a non-idempotent request to be retried: it is called before sending the request again
+	IdempotencyCheck IdempotencyCheckFN
+
+	// Set on a request that doesn't modify anything although it isn't a GET (i.e. a search sent as a POST). It is
+	// retried like a GET and doesn't invalidate the cache of the issues.
+	ReadOnly bool
+
+	// The endpoint is resolved against the base URL of the client performing the call
+	endpoint GetEndpointFN
 }
 
-func CreateAPIFromParams(params configuration.JiraAPIResourceParameters, fnBody CreateBodyFN, fnEndpoint GetEndpointFN, fnJsonObj JsonObjectFN, httpMethod string) (JiraAPI, error) {
-	var err error
+func CreateAPI(fnBody CreateBodyFN, fnEndpoint GetEndpointFN, fnJsonObj JsonObjectFN, httpMethod string) (JiraAPI, error) {
 	api := JiraAPI{}
 
 	if fnBody != nil {
@@ -47,42 +52,47 @@ func CreateAPIFromParams(params configuration.JiraAPIResourceParameters, fnBody
 
 	if fnEndpoint == nil {
 		return api, errors.New("not allowed to have null endpoint creation function")
-	} else if helpers.IsStringPtrNilOrEmtpy(params.JiraAPIUrl) {
-		return api, errors.New("jira API URL was not specified in the parameters")
-	} else {
-		api.url = fnEndpoint(*params.JiraAPIUrl)
 	}
+	api.endpoint = fnEndpoint
 
-	return api, err
+	return api, nil
 }
 
-func (api *JiraAPI) Call() (interface{}, error) {
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+// Sends the request with the client and decodes the response. The request is aborted when the context is done.
+func (api *JiraAPI) Call(ctx context.Context, client *Client) (interface{}, error) {
+	if client == nil {
+		return nil, errors.New("no client to perform the api call with")
 	}
 
-	client := http.Client{Transport: tr}
-	req, newRequestErr := http.NewRequest(api.HttpMethod, api.url, bytes.NewBuffer(api.Body))
-
-	if newRequestErr != nil {
-		return nil, newRequestErr
+	url := api.endpoint(client.BaseURL)
+	if client.DryRun != nil && api.HttpMethod != http.MethodGet && !api.ReadOnly {
+		// The service goes on with an empty response
+		client.DryRun.Plan(api.HttpMethod, url, api.Body)
+		return api.JsonObject, nil
 	}
 
-	if req != nil {
-		req.Header.Set("Content-Type", "application/json")
-
-		log.Logger.Debug("Setting http basic auth for api call")
-		req.SetBasicAuth(auth.Username, auth.Password)
+	if client.Cache != nil {
+		if api.HttpMethod == http.MethodGet {
+			if body, ok := client.Cache.Get(url); ok {
+				log.Logger.Debug("Issue served from the cache of the run")
+				return api.JsonObject, json.Unmarshal(body, &api.JsonObject)
+			}
+		} else if !api.ReadOnly {
+			// Even a failed mutation may have been applied
+			defer client.Cache.Invalidate(url)
+		}
 	}
 
-	log.Logger.Infof("Sending %s request", api.HttpMethod)
-	resp, errDo := client.Do(req)
+	resp, errDo := api.sendWithRetries(ctx, client)
 
 	if errDo != nil {
 		return nil, errDo
+	} else if resp == nil {
+		// A previous attempt of the request was already applied
+		return api.JsonObject, nil
 	}
 
-	defer resp.Body.Close()
+	defer helpers.DrainAndClose(resp.Body)
 	log.Logger.Infof("Received response with %s", fmt.Sprintf("HTTP %s", resp.Status))
 
 	canProcessBody, err := api.processResponse(resp)
@@ -102,12 +112,78 @@ func (api *JiraAPI) Call() (interface{}, error) {
 			log.Logger.Error("Unable to read body of response")
 			return nil, readBodyErr
 		}
-		err = json.Unmarshal(buffer.Bytes(), &api.JsonObject)
+		if buffer.Len() > 0 {
+			// Some successful responses have no body at all (i.e. a property set with HTTP 200 or 201)
+			err = json.Unmarshal(buffer.Bytes(), &api.JsonObject)
+		}
+
+		if err == nil && buffer.Len() > 0 && client.Cache != nil && api.HttpMethod == http.MethodGet {
+			client.Cache.Put(url, buffer.Bytes())
+		}
 	}
 
 	return api.JsonObject, err
 }
 
+// Sends the request and sends it again, according to the retry policy of the client, after a network error, a
+// rate-limit or a server error. Non-idempotent requests are only retried when they provide an idempotency check, and
+// a nil response is returned when that check reports the request as already applied.
+func (api *JiraAPI) sendWithRetries(ctx context.Context, client *Client) (*http.Response, error) {
+	policy := client.Retry
+	idempotent := isIdempotentMethod(api.HttpMethod) || api.ReadOnly
+	canRetry := idempotent || api.IdempotencyCheck != nil
+
+	for retry := 0; ; retry++ {
+		if retry > 0 && !idempotent {
+			// The check must see the effect of the previous attempt, not a response cached before it
+			if client.Cache != nil {
+				client.Cache.Invalidate(api.endpoint(client.BaseURL))
+			}
+
+			applied, err := api.IdempotencyCheck(ctx, client)
+			if err != nil {
+				return nil, err
+			} else if applied {
+				log.Logger.Infof("The %s request was applied by a previous attempt, it isn't sent again", api.HttpMethod)
+				return nil, nil
+			}
+		}
+
+		resp, err := api.send(ctx, client)
+
+		if !canRetry || retry >= policy.Retries || ctx.Err() != nil || (err == nil && !IsRetryable(resp)) {
+			return resp, err
+		}
+
+		delay := policy.Delay(retry+1, resp)
+		if err != nil {
+			log.Logger.Warningf("%s request failed (%v), retrying in %s (%d/%d)", api.HttpMethod, err, delay, retry+1, policy.Retries)
+		} else {
+			log.Logger.Warningf("%s request failed (HTTP %s), retrying in %s (%d/%d)", api.HttpMethod, resp.Status, delay, retry+1, policy.Retries)
+			helpers.DrainAndClose(resp.Body)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (api *JiraAPI) send(ctx context.Context, client *Client) (*http.Response, error) {
+	req, err := http.NewRequest(api.HttpMethod, api.endpoint(client.BaseURL), bytes.NewBuffer(api.Body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Content-Type", "application/json")
+
+	log.Logger.Infof("Sending %s request", api.HttpMethod)
+	return client.Do(req)
+}
+
 func (api *JiraAPI) processResponse(resp *http.Response) (bool, error) {
 	if resp == nil {
 		return false, errors.New("nil response was returned")
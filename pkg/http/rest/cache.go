@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"regexp"
+	"sync"
+)
+
+// Matches the URL of an issue (i.e. .../issue/ABC-123?expand=names) or of one of its sub-resources (i.e.
+// .../issue/ABC-123/comment or .../request/ABC-123/comment for the service desk API). The issue is referenced by
+// its key or its id.
+var issueURL = regexp.MustCompile(`/(issue|request)/([A-Za-z][A-Za-z0-9_]*-[0-9]+|[0-9]+)(/[^?]*)?(\?.*)?$`)
+
+// The IssueCache holds the responses of the GET requests on issues for the duration of a run, so that the services
+// and steps reading the same issue share a single request. Every entry of an issue is dropped as soon as a request
+// that may modify it (PUT, POST, DELETE on the issue or one of its sub-resources) is sent. It is safe to use from
+// concurrent workers.
+type IssueCache struct {
+	mutex sync.Mutex
+
+	// Bodies of the responses by URL, grouped by issue
+	entries map[string]map[string][]byte
+}
+
+func NewIssueCache() *IssueCache {
+	return &IssueCache{entries: make(map[string]map[string][]byte)}
+}
+
+// Returns the body of the response previously received for the URL, if any
+func (c *IssueCache) Get(url string) ([]byte, bool) {
+	issue, cacheable := issueOf(url)
+	if !cacheable {
+		return nil, false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	body, ok := c.entries[issue][url]
+	return body, ok
+}
+
+// Stores the body of the response of a GET request. Only the requests on the issue itself are cached, not the ones
+// on its sub-resources.
+func (c *IssueCache) Put(url string, body []byte) {
+	issue, cacheable := issueOf(url)
+	if !cacheable {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.entries[issue] == nil {
+		c.entries[issue] = make(map[string][]byte)
+	}
+	c.entries[issue][url] = body
+}
+
+// Drops every entry of the issue targeted by the URL of a mutation
+func (c *IssueCache) Invalidate(url string) {
+	m := issueURL.FindStringSubmatch(url)
+	if m == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, m[2])
+}
+
+// Returns the issue targeted by the URL and whether its response can be cached
+func issueOf(url string) (string, bool) {
+	m := issueURL.FindStringSubmatch(url)
+	if m == nil || m[1] != "issue" || m[3] != "" {
+		return "", false
+	}
+
+	return m[2], true
+}
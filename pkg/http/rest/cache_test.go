@@ -0,0 +1,78 @@
+package rest_test
+
+import (
+	"context"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIssueCache(t *testing.T) {
+	t.Run("only the issue itself is cached", func(t *testing.T) {
+		cache := rest.NewIssueCache()
+
+		cache.Put("https://jira.com/rest/api/2/issue/ABC-123?expand=names", []byte(`{}`))
+		cache.Put("https://jira.com/rest/api/2/issue/ABC-123/comment", []byte(`{}`))
+		cache.Put("https://jira.com/rest/api/2/search?jql=x", []byte(`{}`))
+
+		_, ok := cache.Get("https://jira.com/rest/api/2/issue/ABC-123?expand=names")
+		assert.True(t, ok)
+		_, ok = cache.Get("https://jira.com/rest/api/2/issue/ABC-123")
+		assert.False(t, ok)
+		_, ok = cache.Get("https://jira.com/rest/api/2/issue/ABC-123/comment")
+		assert.False(t, ok)
+		_, ok = cache.Get("https://jira.com/rest/api/2/search?jql=x")
+		assert.False(t, ok)
+	})
+
+	t.Run("a mutation of the issue or of a sub-resource invalidates every entry of the issue", func(t *testing.T) {
+		for _, mutation := range []string{
+			"https://jira.com/rest/api/2/issue/ABC-123",
+			"https://jira.com/rest/api/2/issue/ABC-123/transitions",
+			"https://jira.com/rest/servicedeskapi/request/ABC-123/comment",
+		} {
+			cache := rest.NewIssueCache()
+			cache.Put("https://jira.com/rest/api/2/issue/ABC-123?expand=names", []byte(`{}`))
+			cache.Put("https://jira.com/rest/api/2/issue/ABC-123?fields=status", []byte(`{}`))
+			cache.Put("https://jira.com/rest/api/2/issue/ABC-124?expand=names", []byte(`{}`))
+
+			cache.Invalidate(mutation)
+
+			_, ok := cache.Get("https://jira.com/rest/api/2/issue/ABC-123?expand=names")
+			assert.False(t, ok, mutation)
+			_, ok = cache.Get("https://jira.com/rest/api/2/issue/ABC-123?fields=status")
+			assert.False(t, ok, mutation)
+			_, ok = cache.Get("https://jira.com/rest/api/2/issue/ABC-124?expand=names")
+			assert.True(t, ok, mutation)
+		}
+	})
+}
+
+func TestJiraAPI_CallWithCache(t *testing.T) {
+	calls := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.Method]++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"key":"ABC-123"}`))
+	}))
+	defer server.Close()
+
+	client := &rest.Client{BaseURL: server.URL, HTTPClient: server.Client(), Cache: rest.NewIssueCache()}
+	call := func(method string) map[string]interface{} {
+		api, _ := rest.CreateAPI(nil, func(url string) string { return url + "/issue/ABC-123?expand=names" }, func() interface{} { return &map[string]interface{}{} }, method)
+
+		result, err := api.Call(context.Background(), client)
+		assert.NoError(t, err)
+		return *result.(*map[string]interface{})
+	}
+
+	assert.Equal(t, "ABC-123", call(http.MethodGet)["key"])
+	assert.Equal(t, "ABC-123", call(http.MethodGet)["key"])
+	assert.Equal(t, 1, calls[http.MethodGet])
+
+	call(http.MethodPut)
+	call(http.MethodGet)
+	assert.Equal(t, 2, calls[http.MethodGet])
+}
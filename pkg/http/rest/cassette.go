@@ -0,0 +1,264 @@
+package rest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Size of the buffer reading a line of a cassette, a line holding a whole exchange
+const maxCassetteLine = 64 * 1024 * 1024
+
+// An Interaction is one exchange with Jira as it is stored in a cassette: one JSON object per line
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// The CassetteTransport records every exchange with Jira in a cassette file, or replays them from that file without
+// any network access. The credentials (authorization headers, cookies, tokens, passwords...) are scrubbed before an
+// exchange is written, along with the values of the custom headers of the client, so a cassette recorded in production
+// can be shared to reproduce a run locally.
+//
+// On replay, a request is matched by its method and (scrubbed) URL. Identical requests get the recorded responses in
+// the order they were recorded, the last one being repeated.
+type CassetteTransport struct {
+	mode      string
+	path      string
+	transport http.RoundTripper
+
+	// Canonical names of the headers scrubbed whatever their name
+	secretHeaders map[string]bool
+
+	mutex        sync.Mutex
+	interactions map[string][]Interaction
+}
+
+// Creates a transport recording the exchanges sent through the transport, or replaying the ones of the cassette. The
+// values of the secret headers (i.e. the custom headers of the client, which may carry a gateway key) are scrubbed
+// along with the credentials.
+func NewCassetteTransport(mode, path string, transport http.RoundTripper, secretHeaders []string) (*CassetteTransport, error) {
+	c := &CassetteTransport{
+		mode:          mode,
+		path:          path,
+		transport:     transport,
+		secretHeaders: make(map[string]bool),
+		interactions:  make(map[string][]Interaction),
+	}
+
+	for _, name := range secretHeaders {
+		c.secretHeaders[http.CanonicalHeaderKey(name)] = true
+	}
+
+	switch mode {
+	case configuration.CassetteRecord:
+		// A new recording replaces the previous one
+		if err := ioutil.WriteFile(path, nil, 0600); err != nil {
+			return nil, err
+		}
+	case configuration.CassetteReplay:
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported cassette mode: %s", mode)
+	}
+
+	return c, nil
+}
+
+func (c *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if c.mode == configuration.CassetteReplay {
+		return c.replay(req)
+	}
+
+	return c.record(req)
+}
+
+func (c *CassetteTransport) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     RedactURL(req.URL.String()),
+			Headers: c.scrubHeaders(req.Header),
+			Body:    scrubBody(reqBody, req.Header.Get("Content-Type")),
+		},
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: c.scrubHeaders(resp.Header),
+			Body:    scrubBody(respBody, resp.Header.Get("Content-Type")),
+		},
+	}
+
+	return resp, c.append(interaction)
+}
+
+func (c *CassetteTransport) replay(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	key := cassetteKey(req.Method, RedactURL(req.URL.String()))
+
+	c.mutex.Lock()
+	recorded := c.interactions[key]
+	if len(recorded) == 0 {
+		c.mutex.Unlock()
+		return nil, fmt.Errorf("no exchange recorded in the cassette for %s", key)
+	}
+
+	interaction := recorded[0]
+	if len(recorded) > 1 {
+		c.interactions[key] = recorded[1:]
+	}
+	c.mutex.Unlock()
+
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	header := make(http.Header)
+	for k, v := range interaction.Response.Headers {
+		header[k] = v
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+		StatusCode:    interaction.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Appends an exchange to the cassette right away, so that a run that is interrupted is still recorded
+func (c *CassetteTransport) append(interaction Interaction) error {
+	line, err := json.Marshal(interaction)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	f, err := os.OpenFile(c.path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+func (c *CassetteTransport) load() error {
+	f, err := os.Open(c.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxCassetteLine)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var interaction Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return fmt.Errorf("invalid exchange at line %d of the cassette: %v", line, err)
+		}
+
+		key := cassetteKey(interaction.Request.Method, interaction.Request.URL)
+		c.interactions[key] = append(c.interactions[key], interaction)
+	}
+
+	return scanner.Err()
+}
+
+func cassetteKey(method, url string) string {
+	return method + " " + url
+}
+
+// Returns the body without the values of the credentials it holds, such as the secrets of an OAuth 2.0 token request
+func scrubBody(body []byte, contentType string) string {
+	if !strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return RedactBody(body)
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return redacted
+	}
+
+	for k := range form {
+		if sensitiveName.MatchString(k) {
+			form.Set(k, redacted)
+		}
+	}
+
+	return form.Encode()
+}
+
+// Returns a copy of the headers without the values of the secret ones and of the ones carrying credentials
+func (c *CassetteTransport) scrubHeaders(header http.Header) http.Header {
+	scrubbed := make(http.Header)
+	for k, v := range header {
+		if c.secretHeaders[http.CanonicalHeaderKey(k)] || sensitiveName.MatchString(k) || strings.Contains(strings.ToLower(k), "cookie") {
+			scrubbed[k] = []string{redacted}
+		} else {
+			scrubbed[k] = v
+		}
+	}
+
+	return scrubbed
+}
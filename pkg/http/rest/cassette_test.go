@@ -0,0 +1,98 @@
+package rest_test
+
+import (
+	"context"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/auth"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/reading"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newCassetteClient(t *testing.T, baseURL, mode, path string, transport http.RoundTripper) *rest.Client {
+	// The gateway header is configured (i.e. 'source.headers'), its name doesn't tell it carries a credential
+	cassette, err := rest.NewCassetteTransport(mode, path, transport, []string{"x-gateway"})
+	require.NoError(t, err)
+
+	return &rest.Client{
+		BaseURL:       baseURL,
+		Authenticator: auth.Bearer{Token: "s3cr3t-token"},
+		HTTPClient:    &http.Client{Transport: cassette},
+		Headers:       http.Header{"X-Api-Key": {"s3cr3t-api-key"}, "X-Gateway": {"s3cr3t-gateway"}},
+	}
+}
+
+func fetchIssue(client *rest.Client, key string) (map[string]string, error) {
+	params := configuration.JiraAPIResourceParameters{ActiveIssue: key}
+	srv := &reading.ServiceFetchIssueData{}
+
+	err := service.Execute(context.Background(), client, srv, params, false)
+	return srv.GetResults(), err
+}
+
+func TestCassetteTransport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "run.jsonl")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cr3t-token" || r.Header.Get("X-Api-Key") != "s3cr3t-api-key" || r.Header.Get("X-Gateway") != "s3cr3t-gateway" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "JSESSIONID=s3cr3t-session")
+		if r.URL.Path == "/issue/ABC-2" {
+			_, _ = w.Write([]byte(`{"key":"ABC-2","fields":{"status":{"name":"Closed"},"parent":{"key":"ABC-1"}}}`))
+		} else {
+			_, _ = w.Write([]byte(`{"key":"ABC-1","fields":{"status":{"name":"Open"}}}`))
+		}
+	}))
+	baseURL := server.URL
+
+	recording := newCassetteClient(t, baseURL, configuration.CassetteRecord, path, server.Client().Transport)
+	recorded1, err := fetchIssue(recording, "ABC-1")
+	require.NoError(t, err)
+	recorded2, err := fetchIssue(recording, "ABC-2")
+	require.NoError(t, err)
+	server.Close()
+
+	t.Run("credentials are scrubbed", func(t *testing.T) {
+		b, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+
+		assert.NotContains(t, string(b), "s3cr3t")
+		assert.Contains(t, string(b), "REDACTED")
+	})
+
+	t.Run("exchanges are replayed without Jira", func(t *testing.T) {
+		replaying := newCassetteClient(t, baseURL, configuration.CassetteReplay, path, nil)
+
+		replayed2, err := fetchIssue(replaying, "ABC-2")
+		require.NoError(t, err)
+		replayed1, err := fetchIssue(replaying, "ABC-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, recorded1, replayed1)
+		assert.Equal(t, recorded2, replayed2)
+		assert.Equal(t, "ABC-1", replayed2[helpers.ParentIssueKey])
+	})
+
+	t.Run("a request that was not recorded fails", func(t *testing.T) {
+		replaying := newCassetteClient(t, baseURL, configuration.CassetteReplay, path, nil)
+
+		_, err := fetchIssue(replaying, "ABC-3")
+		assert.Error(t, err)
+	})
+}
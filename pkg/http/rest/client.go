@@ -0,0 +1,201 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/auth"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
+	"net/http"
+	"time"
+)
+
+const DefaultRequestTimeout = 60 * time.Second
+
+// A Client holds everything needed to talk to one Jira instance as one identity: the base URL of its API, the
+// authenticator setting the credentials on each request and the underlying http client. It is passed to every
+// service so that nothing related to the connection lives in package-level state.
+type Client struct {
+	BaseURL       string
+	Authenticator auth.Authenticator
+	HTTPClient    *http.Client
+
+	// Extra headers set on every request, such as an API gateway key or the User-Agent
+	Headers http.Header
+
+	// How failed requests are retried
+	Retry RetryPolicy
+
+	// The responses of the GET requests on issues, shared by every service of the run (no caching when nil)
+	Cache *IssueCache
+
+	// Collects the requests modifying Jira instead of sending them (they are sent when nil)
+	DryRun *DryRun
+}
+
+// Creates a client from the input parameters. With the OAuth 2.0 authentication, the access token is obtained
+// right away and the base URL is rewritten to go through the Atlassian API gateway with the cloud id of the site.
+func NewClient(params configuration.JiraAPIResourceParameters) (*Client, error) {
+	if helpers.IsStringPtrNilOrEmtpy(params.JiraAPIUrl) {
+		return nil, errors.New("jira API URL was not specified in the parameters")
+	}
+
+	authenticator, err := NewAuthenticator(params)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := NewTLSConfig(params)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := NewProxyFunc(helpers.StringPtrValue(params.ProxyURL), helpers.StringPtrValue(params.NoProxy))
+	if err != nil {
+		return nil, err
+	}
+
+	extraHeaders, err := params.ExtraHeaders()
+	if err != nil {
+		return nil, err
+	}
+
+	var transport http.RoundTripper = NewTransport(proxy, tlsConfig)
+	if path := helpers.StringPtrValue(params.Cassette); path != "" {
+		secretHeaders := make([]string, 0, len(extraHeaders))
+		for k := range extraHeaders {
+			secretHeaders = append(secretHeaders, k)
+		}
+
+		if transport, err = NewCassetteTransport(helpers.StringPtrValue(params.CassetteMode), path, transport, secretHeaders); err != nil {
+			return nil, err
+		}
+		log.Logger.Infof("Using the cassette %s in %s mode", path, *params.CassetteMode)
+	}
+
+	client := &Client{
+		BaseURL:       *params.JiraAPIUrl,
+		Authenticator: authenticator,
+		HTTPClient: &http.Client{
+			Transport: transport,
+			Timeout:   requestTimeout(params),
+		},
+		Headers: make(http.Header),
+		Retry:   NewRetryPolicy(params),
+		Cache:   NewIssueCache(),
+	}
+
+	for k, v := range extraHeaders {
+		client.Headers.Set(k, v)
+	}
+
+	if helpers.IsBoolPtrTrue(params.Flags.DryRun) {
+		log.Logger.Warning("Dry run: the requests modifying Jira are logged but not sent")
+		client.DryRun = NewDryRun()
+	}
+
+	if oauth2, ok := authenticator.(*auth.OAuth2Credentials); ok {
+		// The tokens are obtained through the same proxy and with the same TLS configuration as the API calls
+		oauth2.HTTPClient = client.HTTPClient
+
+		id := helpers.StringPtrValue(params.CloudId)
+		if id == "" {
+			if id, err = oauth2.DiscoverCloudId(context.Background(), client.BaseURL); err != nil {
+				return nil, err
+			}
+		}
+
+		client.BaseURL = CloudGatewayURL(oauth2.GatewayURL, id, helpers.StringPtrValue(params.APIVersion))
+		log.Logger.Infof("Using cloud id %s, the Jira API is reached through %s", id, client.BaseURL)
+	}
+
+	return client, nil
+}
+
+// Returns the maximum duration of a single request, reading the response included (0 means no limit)
+func requestTimeout(params configuration.JiraAPIResourceParameters) time.Duration {
+	d, err := helpers.ParseDuration(helpers.StringPtrValue(params.RequestTimeout))
+	if err != nil {
+		return DefaultRequestTimeout
+	}
+
+	return d
+}
+
+// Creates the authenticator matching the authentication type of the input parameters
+func NewAuthenticator(params configuration.JiraAPIResourceParameters) (auth.Authenticator, error) {
+	switch t := helpers.StringPtrValue(params.AuthType); t {
+	case "", auth.TypeBasic:
+		return auth.Basic{
+			Username: helpers.StringPtrValue(params.Username),
+			Password: helpers.StringPtrValue(params.Password),
+		}, nil
+	case auth.TypeBearer:
+		return auth.Bearer{Token: helpers.StringPtrValue(params.Token)}, nil
+	case auth.TypeOAuth1:
+		key, err := auth.ParsePrivateKey(helpers.StringPtrValue(params.PrivateKey))
+		if err != nil {
+			return nil, err
+		}
+
+		return auth.OAuth1Credentials{
+			ConsumerKey: helpers.StringPtrValue(params.ConsumerKey),
+			PrivateKey:  key,
+			AccessToken: helpers.StringPtrValue(params.AccessToken),
+			TokenSecret: helpers.StringPtrValue(params.TokenSecret),
+		}, nil
+	case auth.TypeOAuth2:
+		return &auth.OAuth2Credentials{
+			ClientId:     helpers.StringPtrValue(params.ClientId),
+			ClientSecret: helpers.StringPtrValue(params.ClientSecret),
+			RefreshToken: helpers.StringPtrValue(params.RefreshToken),
+			TokenURL:     helpers.StringPtrValue(params.TokenURL),
+			GatewayURL:   helpers.StringPtrValue(params.GatewayURL),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported authentication type: %s", t)
+	}
+}
+
+// Sets the extra headers, authenticates and sends the request
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	for k, values := range c.Headers {
+		req.Header[k] = values
+	}
+
+	if c.Authenticator != nil {
+		log.Logger.Debug("Setting http authorization for api call")
+		if err := c.Authenticator.Authenticate(req); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	renewable, ok := c.Authenticator.(auth.RenewableAuthenticator)
+	if !ok || (req.Body != nil && req.GetBody == nil) {
+		return resp, err
+	}
+
+	// The credentials were rejected before their expiry (i.e. a revoked token): the request is sent once more with
+	// renewed credentials
+	helpers.DrainAndClose(resp.Body)
+	renewable.Invalidate(req)
+
+	retry := req.WithContext(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	if err := renewable.Authenticate(retry); err != nil {
+		return nil, err
+	}
+
+	return c.HTTPClient.Do(retry)
+}
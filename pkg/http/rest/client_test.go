@@ -0,0 +1,154 @@
+package rest_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/auth"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Local stub of a Jira instance answering GET /myself with the identity found in the 'Authorization' header
+func newIdentityServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.Header.Get("Authorization")
+		if username, _, ok := r.BasicAuth(); ok {
+			name = username
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"name": %q}`, name)
+	}))
+}
+
+func callMyself(t *testing.T, client *rest.Client) string {
+	api, err := rest.CreateAPI(nil, func(url string) string { return url + "/myself" }, func() interface{} { return nil }, http.MethodGet)
+	require.NoError(t, err)
+
+	result, err := api.Call(context.Background(), client)
+	require.NoError(t, err)
+
+	return result.(map[string]interface{})["name"].(string)
+}
+
+func TestClient_Call(t *testing.T) {
+	first := newIdentityServer()
+	defer first.Close()
+	second := newIdentityServer()
+	defer second.Close()
+
+	t.Run("two clients with their own base URL and credentials in one run", func(t *testing.T) {
+		basic := &rest.Client{BaseURL: first.URL, Authenticator: auth.Basic{Username: "bot", Password: "secret"}, HTTPClient: first.Client()}
+		bearer := &rest.Client{BaseURL: second.URL, Authenticator: auth.Bearer{Token: "pat"}, HTTPClient: second.Client()}
+
+		assert.Equal(t, "bot", callMyself(t, basic))
+		assert.Equal(t, "Bearer pat", callMyself(t, bearer))
+		assert.Equal(t, "bot", callMyself(t, basic))
+	})
+
+	t.Run("call without client", func(t *testing.T) {
+		api, err := rest.CreateAPI(nil, func(url string) string { return url }, nil, http.MethodGet)
+		require.NoError(t, err)
+
+		_, err = api.Call(context.Background(), nil)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	authType := auth.TypeBearer
+	token := "pat"
+	params := configuration.JiraAPIResourceParameters{AuthType: &authType, Token: &token}
+
+	authenticator, err := rest.NewAuthenticator(params)
+
+	require.NoError(t, err)
+	assert.Equal(t, auth.Bearer{Token: "pat"}, authenticator)
+}
+
+// Local stub of the OAuth 2.0 token endpoint and of a Jira instance answering GET /myself. The tokens listed in
+// 'revoked' are rejected with HTTP 401 although they haven't expired.
+type oauth2Server struct {
+	*httptest.Server
+	issued  int
+	revoked map[string]bool
+	posts   []string
+}
+
+func newOAuth2Server(revoked ...string) *oauth2Server {
+	s := &oauth2Server{revoked: make(map[string]bool)}
+	for _, token := range revoked {
+		s.revoked[token] = true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		s.issued++
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3600}`, s.issued)
+	})
+	mux.HandleFunc("/myself", func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if r.Method == http.MethodPost {
+			body, _ := ioutil.ReadAll(r.Body)
+			s.posts = append(s.posts, string(body))
+		}
+		if s.revoked[token] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"name": %q}`, token)
+	})
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func newOAuth2Client(server *oauth2Server) *rest.Client {
+	credentials := &auth.OAuth2Credentials{ClientId: "client", ClientSecret: "secret", TokenURL: server.URL + "/oauth/token"}
+	return &rest.Client{BaseURL: server.URL, Authenticator: credentials, HTTPClient: server.Client()}
+}
+
+func TestClient_Do(t *testing.T) {
+	t.Run("a token rejected before its expiry is renewed and the request sent again", func(t *testing.T) {
+		server := newOAuth2Server("token-1")
+		defer server.Close()
+
+		assert.Equal(t, "token-2", callMyself(t, newOAuth2Client(server)))
+		assert.Equal(t, 2, server.issued)
+	})
+
+	t.Run("the body is sent again with the renewed token", func(t *testing.T) {
+		server := newOAuth2Server("token-1")
+		defer server.Close()
+
+		api, err := rest.CreateAPI(func() []byte { return []byte(`{"a":1}`) }, func(url string) string { return url + "/myself" },
+			func() interface{} { return nil }, http.MethodPost)
+		require.NoError(t, err)
+
+		_, err = api.Call(context.Background(), newOAuth2Client(server))
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{`{"a":1}`, `{"a":1}`}, server.posts)
+	})
+
+	t.Run("the request is sent only once more when the renewed token is rejected too", func(t *testing.T) {
+		server := newOAuth2Server("token-1", "token-2", "token-3")
+		defer server.Close()
+
+		api, err := rest.CreateAPI(nil, func(url string) string { return url + "/myself" }, func() interface{} { return nil }, http.MethodGet)
+		require.NoError(t, err)
+
+		_, err = api.Call(context.Background(), newOAuth2Client(server))
+
+		assert.Error(t, err)
+		assert.Equal(t, 2, server.issued)
+	})
+}
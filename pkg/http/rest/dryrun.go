@@ -0,0 +1,160 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
+	"net/url"
+	"regexp"
+	"sync"
+)
+
+const redacted = "REDACTED"
+
+// Names of the query parameters and JSON properties whose values are never logged
+var sensitiveName = regexp.MustCompile(`(?i)password|secret|token|signature|api[-_]?key|authorization`)
+
+// A PlannedRequest is a request that a dry run didn't send, with its credentials redacted
+type PlannedRequest struct {
+	Issue  string
+	Method string
+	URL    string
+	Body   string
+}
+
+// The DryRun collects the requests that would modify Jira (PUT, POST, DELETE) instead of sending them. The requests
+// reading Jira are still sent so that the plan is accurate. It is safe to use from concurrent workers.
+type DryRun struct {
+	mutex   sync.Mutex
+	planned []PlannedRequest
+}
+
+func NewDryRun() *DryRun {
+	return &DryRun{}
+}
+
+// Logs and records a request that is not sent
+func (d *DryRun) Plan(method, rawURL string, body []byte) {
+	p := PlannedRequest{Method: method, URL: RedactURL(rawURL), Body: RedactBody(body)}
+	if m := issueURL.FindStringSubmatch(rawURL); m != nil {
+		p.Issue = m[2]
+	}
+
+	log.Logger.Infof("[dry run] %s %s %s", p.Method, p.URL, p.Body)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.planned = append(d.planned, p)
+}
+
+// Returns the requests that were not sent, in the order they were planned
+func (d *DryRun) Planned() []PlannedRequest {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return append([]PlannedRequest{}, d.planned...)
+}
+
+// Logs what would have changed, issue by issue, in the order of the issue list. The requests that don't target a
+// specific issue (i.e. a bulk creation) are listed last.
+func (d *DryRun) LogSummary(issues []string) {
+	planned := d.Planned()
+	byIssue := make(map[string][]PlannedRequest)
+	for _, p := range planned {
+		byIssue[p.Issue] = append(byIssue[p.Issue], p)
+	}
+
+	log.Logger.Infof("[dry run] %d request(s) modifying Jira were not sent", len(planned))
+
+	listed := make(map[string]bool)
+	for _, issue := range append(append([]string{}, issues...), "") {
+		if listed[issue] {
+			continue
+		}
+		listed[issue] = true
+
+		name := issue
+		if name == "" {
+			name = "(no issue)"
+		}
+
+		if len(byIssue[issue]) == 0 {
+			if issue != "" {
+				log.Logger.Infof("[dry run] %s: no change", name)
+			}
+			continue
+		}
+
+		for _, p := range byIssue[issue] {
+			log.Logger.Infof("[dry run] %s: %s %s", name, p.Method, p.URL)
+		}
+	}
+
+	// Issues that were not in the list, such as the parents of the issues with '--forceOnParent'
+	for _, p := range planned {
+		if !listed[p.Issue] {
+			log.Logger.Infof("[dry run] %s: %s %s", p.Issue, p.Method, p.URL)
+		}
+	}
+}
+
+// Removes the user info and the values of the sensitive query parameters from the URL
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	if u.User != nil {
+		u.User = url.User(redacted)
+	}
+
+	query := u.Query()
+	for k := range query {
+		if sensitiveName.MatchString(k) {
+			query.Set(k, redacted)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// Replaces the values of the sensitive properties of a JSON body. A body that isn't JSON is returned as is.
+func RedactBody(body []byte) string {
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// The numbers (i.e. ids) are kept as they were written
+	decoder.UseNumber()
+	if len(body) == 0 || decoder.Decode(&v) != nil {
+		return string(body)
+	}
+
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+
+	return string(b)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, e := range value {
+			if sensitiveName.MatchString(k) {
+				value[k] = redacted
+			} else {
+				value[k] = redactValue(e)
+			}
+		}
+	case []interface{}:
+		for i, e := range value {
+			value[i] = redactValue(e)
+		}
+	}
+
+	return v
+}
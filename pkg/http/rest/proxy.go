@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// The proxy function of the transport. Without an explicit proxy URL the usual HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// environment variables are honored. With one, every request goes through it except for the hosts matching
+// 'noProxy' (or the NO_PROXY environment variable when 'noProxy' is empty).
+func NewProxyFunc(proxyURL, noProxy string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxy, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if noProxy == "" {
+		noProxy = os.Getenv("NO_PROXY")
+		if noProxy == "" {
+			noProxy = os.Getenv("no_proxy")
+		}
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if MatchesNoProxy(req.URL.Host, noProxy) {
+			return nil, nil
+		}
+
+		return proxy, nil
+	}, nil
+}
+
+// Returns true if the host (with an optional port) must be reached directly according to the comma-separated
+// 'noProxy' list. The semantics are the ones of the NO_PROXY environment variable:
+//   - '*' matches every host
+//   - a domain name matches itself and its subdomains ('.example.com' and 'example.com' are equivalent)
+//   - an IP address or a CIDR range matches the IP addresses it contains
+//   - an entry with a port only matches that port
+//
+// Loopback hosts are always reached directly.
+func MatchesNoProxy(host, noProxy string) bool {
+	hostname, port := host, ""
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		hostname, port = h, p
+	}
+	hostname = strings.ToLower(strings.Trim(hostname, "[]"))
+
+	if hostname == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(hostname)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		} else if entry == "*" {
+			return true
+		}
+
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		entryHost, entryPort := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			entryHost, entryPort = h, p
+		}
+		entryHost = strings.Trim(entryHost, "[]")
+
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+
+		if entryIP := net.ParseIP(entryHost); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		entryHost = strings.TrimPrefix(entryHost, ".")
+		if hostname == entryHost || strings.HasSuffix(hostname, "."+entryHost) {
+			return true
+		}
+	}
+
+	return false
+}
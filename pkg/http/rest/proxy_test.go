@@ -0,0 +1,73 @@
+package rest_test
+
+import (
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMatchesNoProxy(t *testing.T) {
+	noProxy := ".internal.example.com, jira.example.org, 10.0.0.0/8, 192.168.1.10, gateway.example.net:8443"
+
+	tests := []struct {
+		host     string
+		expected bool
+	}{
+		{"jira.internal.example.com", true},
+		{"internal.example.com:443", true},
+		{"notinternal.example.com", false},
+		{"jira.example.org", true},
+		{"api.jira.example.org", true},
+		{"example.org", false},
+		{"10.1.2.3:8080", true},
+		{"11.1.2.3", false},
+		{"192.168.1.10", true},
+		{"192.168.1.11", false},
+		{"gateway.example.net:8443", true},
+		{"gateway.example.net:443", false},
+		{"localhost:8080", true},
+		{"127.0.0.1", true},
+		{"[::1]:8080", true},
+		{"company.atlassian.net", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.host, func(t *testing.T) {
+			assert.Equal(t, test.expected, rest.MatchesNoProxy(test.host, noProxy))
+		})
+	}
+
+	t.Run("wildcard", func(t *testing.T) {
+		assert.True(t, rest.MatchesNoProxy("company.atlassian.net", "*"))
+	})
+}
+
+func TestClient_ProxyAndHeaders(t *testing.T) {
+	var proxied *http.Request
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r
+	}))
+	defer proxy.Close()
+
+	params := newTLSParams("http://jira.example.com/rest/api/2")
+	proxyURL, noProxy := proxy.URL, "direct.example.com"
+	headers, userAgent := `{"X-Api-Key": "gateway-key"}`, "concourse/deploy-pipeline"
+	params.ProxyURL, params.NoProxy, params.Headers, params.UserAgent = &proxyURL, &noProxy, &headers, &userAgent
+
+	client, err := rest.NewClient(params)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, client.BaseURL+"/myself", nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.NotNil(t, proxied, "the request didn't go through the proxy")
+	assert.Equal(t, "jira.example.com", proxied.Host)
+	assert.Equal(t, "gateway-key", proxied.Header.Get("X-Api-Key"))
+	assert.Equal(t, "concourse/deploy-pipeline", proxied.Header.Get("User-Agent"))
+}
@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"context"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultRetries        = 3
+	DefaultRetryBaseDelay = time.Second
+	DefaultRetryMaxDelay  = 30 * time.Second
+)
+
+// Checks whether a non-idempotent request (i.e. a POST) that failed was nonetheless applied by Jira, in which case it
+// must not be sent again. Returns true if the request was already applied.
+type IdempotencyCheckFN func(ctx context.Context, client *Client) (bool, error)
+
+// The RetryPolicy defines how many times, and after which delay, a request is sent again after a network error, a
+// rate-limit (HTTP 429) or a server error (HTTP 5xx). The delay grows exponentially from BaseDelay, up to MaxDelay,
+// with a random jitter so that concurrent runs don't retry in lockstep. The delay requested by Jira through the
+// 'Retry-After' or 'X-RateLimit-Reset' headers takes precedence.
+type RetryPolicy struct {
+	Retries   int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Returns the policy used when nothing was configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{Retries: DefaultRetries, BaseDelay: DefaultRetryBaseDelay, MaxDelay: DefaultRetryMaxDelay}
+}
+
+// Creates the retry policy from the input parameters. The default values are used for the missing ones.
+func NewRetryPolicy(params configuration.JiraAPIResourceParameters) RetryPolicy {
+	policy := DefaultRetryPolicy()
+
+	if params.Retries != nil {
+		policy.Retries = *params.Retries
+	}
+
+	if d, err := helpers.ParseDuration(helpers.StringPtrValue(params.RetryBaseDelay)); err == nil {
+		policy.BaseDelay = d
+	}
+
+	if d, err := helpers.ParseDuration(helpers.StringPtrValue(params.RetryMaxDelay)); err == nil {
+		policy.MaxDelay = d
+	}
+
+	return policy
+}
+
+// Returns the delay to wait before the specified retry (starting at 1) of a request that got the response (which is
+// nil after a network error)
+func (p RetryPolicy) Delay(retry int, resp *http.Response) time.Duration {
+	if d, ok := requestedDelay(resp, time.Now()); ok {
+		return d
+	}
+
+	delay := p.MaxDelay
+	if shift := uint(retry - 1); shift < 32 && p.BaseDelay<<shift > 0 && p.BaseDelay<<shift < p.MaxDelay {
+		delay = p.BaseDelay << shift
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	// Equal jitter: half of the delay is fixed, the other half is random
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// Returns the delay requested by the server, either in seconds or as a date in the 'Retry-After' header, or as the
+// moment the rate-limit is reset in the 'X-RateLimit-Reset' header when no request remains
+func requestedDelay(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+
+		if date, err := http.ParseTime(value); err == nil {
+			return nonNegative(date.Sub(now)), true
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if value := resp.Header.Get("X-RateLimit-Reset"); value != "" {
+			if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return nonNegative(time.Unix(epoch, 0).Sub(now)), true
+			}
+
+			for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00"} {
+				if date, err := time.Parse(layout, value); err == nil {
+					return nonNegative(date.Sub(now)), true
+				}
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+
+	return d
+}
+
+// Returns true for the methods that can safely be sent more than once (RFC 7231 section 4.2.2)
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
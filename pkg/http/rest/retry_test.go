@@ -0,0 +1,162 @@
+package rest_test
+
+import (
+	"context"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Local stub answering with each of the status codes in turn (the last one is repeated)
+type flakyServer struct {
+	*httptest.Server
+	statuses []int
+	calls    int
+}
+
+func newFlakyServer(statuses ...int) *flakyServer {
+	f := &flakyServer{statuses: statuses}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := f.statuses[len(f.statuses)-1]
+		if f.calls < len(f.statuses) {
+			status = f.statuses[f.calls]
+		}
+		f.calls++
+
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+
+	return f
+}
+
+func newRetryingClient(server *flakyServer) *rest.Client {
+	return &rest.Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Retry:      rest.RetryPolicy{Retries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond},
+	}
+}
+
+func call(client *rest.Client, method string, check rest.IdempotencyCheckFN) error {
+	api, _ := rest.CreateAPI(nil, func(url string) string { return url + "/issue/ABC-123" }, func() interface{} { return &map[string]interface{}{} }, method)
+	api.IdempotencyCheck = check
+
+	_, err := api.Call(context.Background(), client)
+	return err
+}
+
+func TestJiraAPI_CallWithRetries(t *testing.T) {
+	t.Run("rate-limited and failing GET is retried until it succeeds", func(t *testing.T) {
+		server := newFlakyServer(http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusOK)
+		defer server.Close()
+
+		assert.NoError(t, call(newRetryingClient(server), http.MethodGet, nil))
+		assert.Equal(t, 3, server.calls)
+	})
+
+	t.Run("GET fails once the retries are exhausted", func(t *testing.T) {
+		server := newFlakyServer(http.StatusBadGateway)
+		defer server.Close()
+
+		assert.Error(t, call(newRetryingClient(server), http.MethodGet, nil))
+		assert.Equal(t, 4, server.calls)
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		server := newFlakyServer(http.StatusNotFound)
+		defer server.Close()
+
+		assert.Error(t, call(newRetryingClient(server), http.MethodGet, nil))
+		assert.Equal(t, 1, server.calls)
+	})
+
+	t.Run("POST without idempotency check is never retried", func(t *testing.T) {
+		server := newFlakyServer(http.StatusServiceUnavailable, http.StatusOK)
+		defer server.Close()
+
+		assert.Error(t, call(newRetryingClient(server), http.MethodPost, nil))
+		assert.Equal(t, 1, server.calls)
+	})
+
+	t.Run("POST is retried when the idempotency check reports it as not applied", func(t *testing.T) {
+		server := newFlakyServer(http.StatusServiceUnavailable, http.StatusOK)
+		defer server.Close()
+		checks := 0
+
+		err := call(newRetryingClient(server), http.MethodPost, func(ctx context.Context, client *rest.Client) (bool, error) {
+			checks++
+			return false, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, server.calls)
+		assert.Equal(t, 1, checks)
+	})
+
+	t.Run("POST is not sent again when the idempotency check reports it as applied", func(t *testing.T) {
+		server := newFlakyServer(http.StatusGatewayTimeout, http.StatusOK)
+		defer server.Close()
+
+		err := call(newRetryingClient(server), http.MethodPost, func(ctx context.Context, client *rest.Client) (bool, error) {
+			return true, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, server.calls)
+	})
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := rest.RetryPolicy{Retries: 5, BaseDelay: time.Second, MaxDelay: 4 * time.Second}
+
+	t.Run("jittered exponential backoff capped by the maximum delay", func(t *testing.T) {
+		for retry, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+			delay := policy.Delay(retry+1, nil)
+			assert.True(t, delay >= max/2 && delay <= max, "retry %d: %s not in [%s, %s]", retry+1, delay, max/2, max)
+		}
+	})
+
+	t.Run("Retry-After in seconds", func(t *testing.T) {
+		resp := &http.Response{Header: http.Header{"Retry-After": []string{"12"}}}
+
+		assert.Equal(t, 12*time.Second, policy.Delay(1, resp))
+	})
+
+	t.Run("Retry-After as a date", func(t *testing.T) {
+		date := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+		resp := &http.Response{Header: http.Header{"Retry-After": []string{date}}}
+
+		delay := policy.Delay(1, resp)
+		assert.True(t, delay > 8*time.Second && delay <= 10*time.Second, "unexpected delay %s", delay)
+	})
+
+	t.Run("rate-limit reset when no request remains", func(t *testing.T) {
+		reset := strconv.FormatInt(time.Now().Add(20*time.Second).Unix(), 10)
+		resp := &http.Response{Header: http.Header{"X-Ratelimit-Remaining": []string{"0"}, "X-Ratelimit-Reset": []string{reset}}}
+
+		delay := policy.Delay(1, resp)
+		assert.True(t, delay > 18*time.Second && delay <= 20*time.Second, "unexpected delay %s", delay)
+	})
+
+	t.Run("rate-limit reset is ignored while requests remain", func(t *testing.T) {
+		resp := &http.Response{Header: http.Header{"X-Ratelimit-Remaining": []string{"10"}, "X-Ratelimit-Reset": []string{"2030-01-01T00:00Z"}}}
+
+		assert.True(t, policy.Delay(1, resp) <= time.Second)
+	})
+}
+
+func TestRetryPolicy_DelayWithoutResponse(t *testing.T) {
+	policy := rest.RetryPolicy{BaseDelay: 0, MaxDelay: 0}
+
+	require.Equal(t, time.Duration(0), policy.Delay(1, nil))
+}
@@ -2,12 +2,22 @@ package rest
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 )
 
+// HTTPError is the error returned for a response with an error status (4xx or 5xx). It holds the body of the response
+// so that a service can handle the error itself (see service.ErrorHandlingService).
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("Received HTTP%d: %s", e.StatusCode, e.Body)
+}
+
 func HasValidContent(resp *http.Response) bool {
 	if resp != nil {
 		code := resp.StatusCode
@@ -22,7 +32,7 @@ func HasValidContent(resp *http.Response) bool {
 func Is4xx(resp *http.Response) (bool, error) {
 	code := resp.StatusCode
 	if code >= http.StatusBadRequest && code <= 499 {
-		return true, errors.New(fmt.Sprintf("Received HTTP%d: %s", code, readerToString(resp.Body)))
+		return true, &HTTPError{StatusCode: code, Body: readerToString(resp.Body)}
 	}
 
 	return false, nil
@@ -31,7 +41,7 @@ func Is4xx(resp *http.Response) (bool, error) {
 func Is5xx(resp *http.Response) (bool, error) {
 	code := resp.StatusCode
 	if code >= http.StatusInternalServerError && code <= 599 {
-		return true, errors.New(fmt.Sprintf("Received HTTP%d: %s", code, readerToString(resp.Body)))
+		return true, &HTTPError{StatusCode: code, Body: readerToString(resp.Body)}
 	}
 
 	return false, nil
@@ -54,3 +64,14 @@ func readerToString(r io.ReadCloser) string {
 		return buffer.String()
 	}
 }
+
+// Returns true if the request may succeed when sent again: the rate-limit was reached (HTTP 429) or the server
+// failed (HTTP 5xx)
+func IsRetryable(resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode <= 599)
+}
@@ -0,0 +1,68 @@
+package rest_test
+
+import (
+	"context"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJiraAPI_CallTimeouts(t *testing.T) {
+	release := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer hung.Close()
+	defer close(release)
+
+	t.Run("request timeout aborts a hung request", func(t *testing.T) {
+		client := &rest.Client{BaseURL: hung.URL, HTTPClient: &http.Client{Timeout: 50 * time.Millisecond}}
+		start := time.Now()
+
+		err := call(client, http.MethodGet, nil)
+
+		assert.Error(t, err)
+		assert.True(t, time.Since(start) < 5*time.Second, "the request wasn't aborted")
+	})
+
+	t.Run("expired run deadline aborts the request and its retries", func(t *testing.T) {
+		client := &rest.Client{
+			BaseURL:    hung.URL,
+			HTTPClient: hung.Client(),
+			Retry:      rest.RetryPolicy{Retries: 5, BaseDelay: time.Minute, MaxDelay: time.Minute},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		api, _ := rest.CreateAPI(nil, func(url string) string { return url }, nil, http.MethodGet)
+		start := time.Now()
+
+		_, err := api.Call(ctx, client)
+
+		assert.Error(t, err)
+		assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+		assert.True(t, time.Since(start) < 5*time.Second, "the request wasn't aborted")
+	})
+
+	t.Run("cancelled run stops waiting for the next retry", func(t *testing.T) {
+		server := newFlakyServer(http.StatusServiceUnavailable)
+		defer server.Close()
+		client := newRetryingClient(server)
+		client.Retry = rest.RetryPolicy{Retries: 5, BaseDelay: time.Minute, MaxDelay: time.Minute}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		api, _ := rest.CreateAPI(nil, func(url string) string { return url }, nil, http.MethodGet)
+		start := time.Now()
+
+		_, err := api.Call(ctx, client)
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, server.calls)
+		assert.True(t, time.Since(start) < 5*time.Second, "the retry wasn't aborted")
+	})
+}
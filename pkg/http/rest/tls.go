@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
+)
+
+// Creates the TLS configuration of the client from the input parameters. The certificate of the server is verified
+// against the system roots, to which the PEM encoded 'caCert' bundle is added when specified. A client certificate
+// and its key are presented to servers requiring mutual TLS. Verification is only skipped when explicitly asked for.
+func NewTLSConfig(params configuration.JiraAPIResourceParameters) (*tls.Config, error) {
+	config := &tls.Config{}
+
+	if caCert := helpers.StringPtrValue(params.CACert); caCert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM([]byte(caCert)) {
+			return nil, errors.New("no valid PEM certificate found in the CA certificate bundle")
+		}
+		config.RootCAs = pool
+	}
+
+	clientCert := helpers.StringPtrValue(params.ClientCert)
+	clientKey := helpers.StringPtrValue(params.ClientKey)
+	if clientCert != "" || clientKey != "" {
+		certificate, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	if helpers.IsBoolPtrTrue(params.Flags.InsecureSkipVerify) {
+		log.Logger.Warning("TLS certificate verification is disabled: any certificate presented by the server is accepted")
+		config.InsecureSkipVerify = true
+	}
+
+	return config, nil
+}
@@ -0,0 +1,127 @@
+package rest_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTLSParams(serverURL string) configuration.JiraAPIResourceParameters {
+	empty := ""
+	insecure := false
+	url := serverURL
+
+	return configuration.JiraAPIResourceParameters{
+		JiraAPIUrl: &url,
+		CACert:     &empty,
+		ClientCert: &empty,
+		ClientKey:  &empty,
+		Flags:      configuration.JiraAPIResourceFlags{InsecureSkipVerify: &insecure},
+	}
+}
+
+func get(t *testing.T, params configuration.JiraAPIResourceParameters) error {
+	client, err := rest.NewClient(params)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, client.BaseURL, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+
+	return err
+}
+
+// Generates a self-signed client certificate and its key, PEM encoded
+func newClientCertificate(t *testing.T) (string, string, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "jira-api-resource"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})), cert
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	t.Run("unknown certificate is rejected by default", func(t *testing.T) {
+		assert.Error(t, get(t, newTLSParams(server.URL)))
+	})
+
+	t.Run("certificate signed by the CA bundle is accepted", func(t *testing.T) {
+		params := newTLSParams(server.URL)
+		params.CACert = &serverCA
+
+		assert.NoError(t, get(t, params))
+	})
+
+	t.Run("unknown certificate is accepted in insecure mode", func(t *testing.T) {
+		params := newTLSParams(server.URL)
+		*params.Flags.InsecureSkipVerify = true
+
+		assert.NoError(t, get(t, params))
+	})
+
+	t.Run("invalid CA bundle", func(t *testing.T) {
+		params := newTLSParams(server.URL)
+		invalid := "not a certificate"
+		params.CACert = &invalid
+
+		_, err := rest.NewClient(params)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("client certificate is presented to a server requiring mutual TLS", func(t *testing.T) {
+		certPEM, keyPEM, cert := newClientCertificate(t)
+		pool := x509.NewCertPool()
+		pool.AddCert(cert)
+
+		mtls := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		mtls.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+		mtls.StartTLS()
+		defer mtls.Close()
+		mtlsCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: mtls.Certificate().Raw}))
+
+		params := newTLSParams(mtls.URL)
+		params.CACert = &mtlsCA
+		assert.Error(t, get(t, params), "the server accepted a connection without client certificate")
+
+		params.ClientCert = &certPEM
+		params.ClientKey = &keyPEM
+		assert.NoError(t, get(t, params))
+	})
+}
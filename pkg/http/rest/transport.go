@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Tuning of the connection pool shared by every request of a run. Each issue takes several calls to the same host, so
+// the idle connections to that host are kept alive (the default of http.Transport is only 2 per host) to avoid paying
+// a TCP and TLS handshake per call.
+const (
+	maxIdleConns          = 32
+	maxIdleConnsPerHost   = 16
+	idleConnTimeout       = 90 * time.Second
+	dialTimeout           = 30 * time.Second
+	keepAlive             = 30 * time.Second
+	tlsHandshakeTimeout   = 10 * time.Second
+	expectContinueTimeout = time.Second
+)
+
+// Creates the transport of the client, with a tuned connection pool, through the proxy and with the TLS configuration
+func NewTransport(proxy func(*http.Request) (*url.URL, error), tlsConfig *tls.Config) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: keepAlive,
+	}
+
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ExpectContinueTimeout: expectContinueTimeout,
+	}
+}
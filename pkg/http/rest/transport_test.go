@@ -0,0 +1,55 @@
+package rest_test
+
+import (
+	"context"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_ConnectionReuse(t *testing.T) {
+	var connections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorMessages":["Issue does not exist"]}`))
+		case "/edit":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"key": "ABC-123", "fields": {"summary": "A summary long enough to need a few reads"}}`))
+		}
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	client := &rest.Client{
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Transport: rest.NewTransport(nil, server.Client().Transport.(*http.Transport).TLSClientConfig)},
+	}
+
+	for i := 0; i < 5; i++ {
+		for _, path := range []string{"/issue", "/edit", "/missing"} {
+			method := http.MethodGet
+			if path == "/edit" {
+				method = http.MethodPut
+			}
+
+			p := path
+			api, _ := rest.CreateAPI(nil, func(url string) string { return url + p }, func() interface{} { return &map[string]interface{}{} }, method)
+			_, _ = api.Call(context.Background(), client)
+		}
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&connections), "a single connection should be used for every call")
+}
@@ -0,0 +1,27 @@
+package rest
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	apiPath         = "/rest/api/"
+	serviceDeskPath = "/rest/servicedeskapi"
+)
+
+// Returns the base URL of the Jira API of a cloud site when it is reached through the Atlassian API gateway, as
+// required by OAuth 2.0 access tokens (i.e. https://api.atlassian.com/ex/jira/{cloudId}/rest/api/3).
+func CloudGatewayURL(gatewayURL, cloudId, apiVersion string) string {
+	return fmt.Sprintf("%s/ex/jira/%s%s%s", strings.TrimSuffix(gatewayURL, "/"), cloudId, apiPath, apiVersion)
+}
+
+// Returns the base URL of the Jira Service Management API from the base URL of the Jira API
+// (i.e. https://jira.com/rest/api/2 becomes https://jira.com/rest/servicedeskapi).
+func ServiceDeskURL(apiURL string) string {
+	if index := strings.Index(apiURL, apiPath); index >= 0 {
+		return apiURL[:index] + serviceDeskPath
+	}
+
+	return strings.TrimSuffix(apiURL, "/") + serviceDeskPath
+}
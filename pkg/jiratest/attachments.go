@@ -0,0 +1,141 @@
+package jiratest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Maximum size of the multipart form of an attachment upload kept in memory
+const maxAttachmentMemory = 32 << 20
+
+// Returns the files attached to the issue
+func (s *Server) Attachments(key string) []Attachment {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	attachments := make([]Attachment, 0)
+	if issue := s.findIssue(key); issue != nil {
+		for _, a := range s.attachments {
+			if a.Issue == issue.Key {
+				attachments = append(attachments, *a)
+			}
+		}
+	}
+
+	return attachments
+}
+
+// Returns the metadata of the files attached to the issue, as returned in its 'attachment' field. Must be called with
+// the mutex held.
+func (s *Server) issueAttachments(key string) []map[string]interface{} {
+	attachments := make([]map[string]interface{}, 0)
+	for _, a := range s.attachments {
+		if a.Issue == key {
+			attachments = append(attachments, s.attachmentJSON(a))
+		}
+	}
+
+	return attachments
+}
+
+func (s *Server) attachmentJSON(a *Attachment) map[string]interface{} {
+	return map[string]interface{}{
+		"self":     s.self("/attachment/" + a.Id),
+		"id":       a.Id,
+		"filename": a.Filename,
+		"author":   a.Author,
+		"created":  formatTime(a.Created),
+		"size":     len(a.Content),
+		"mimeType": a.MimeType,
+		"content":  fmt.Sprintf("%s/secure/attachment/%s/%s", s.URL, a.Id, url.PathEscape(a.Filename)),
+	}
+}
+
+// Attaches the files of the 'file' parts of the multipart request. Like Jira, the request is rejected without the
+// 'X-Atlassian-Token: no-check' header.
+func (s *Server) addAttachments(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	if r.Header.Get("X-Atlassian-Token") != "no-check" {
+		writeError(w, http.StatusForbidden, "XSRF check failed")
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxAttachmentMemory); err != nil || len(r.MultipartForm.File["file"]) == 0 {
+		writeError(w, http.StatusBadRequest, "The request must be a multipart form with at least one 'file' part.")
+		return
+	}
+
+	added := make([]map[string]interface{}, 0)
+	for _, header := range r.MultipartForm.File["file"] {
+		file, err := header.Open()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		content, err := ioutil.ReadAll(file)
+		_ = file.Close()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		mimeType := header.Header.Get("Content-Type")
+		if mimeType == "" {
+			mimeType = http.DetectContentType(content)
+		}
+
+		a := &Attachment{
+			Id:       s.newId(),
+			Issue:    issue.Key,
+			Filename: header.Filename,
+			MimeType: mimeType,
+			Content:  content,
+			Author:   s.myself,
+			Created:  time.Now(),
+		}
+		s.attachments = append(s.attachments, a)
+		added = append(added, s.attachmentJSON(a))
+	}
+
+	writeJSON(w, http.StatusOK, added)
+}
+
+// Returns the index of the attachment of the request, or writes a 404 and returns -1 when it doesn't exist
+func (s *Server) requestAttachment(w http.ResponseWriter, id string) int {
+	for i, a := range s.attachments {
+		if a.Id == id {
+			return i
+		}
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("The attachment with id '%s' does not exist", id))
+	return -1
+}
+
+func (s *Server) getAttachment(w http.ResponseWriter, r *http.Request, vars []string) {
+	if index := s.requestAttachment(w, vars[0]); index >= 0 {
+		writeJSON(w, http.StatusOK, s.attachmentJSON(s.attachments[index]))
+	}
+}
+
+func (s *Server) getAttachmentContent(w http.ResponseWriter, r *http.Request, vars []string) {
+	if index := s.requestAttachment(w, vars[0]); index >= 0 {
+		a := s.attachments[index]
+		w.Header().Set("Content-Type", a.MimeType)
+		_, _ = w.Write(a.Content)
+	}
+}
+
+func (s *Server) deleteAttachment(w http.ResponseWriter, r *http.Request, vars []string) {
+	if index := s.requestAttachment(w, vars[0]); index >= 0 {
+		s.attachments = append(s.attachments[:index], s.attachments[index+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
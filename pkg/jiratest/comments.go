@@ -0,0 +1,219 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Body of the creation and the update of a comment
+type commentRequest struct {
+	Body       json.RawMessage   `json:"body"`
+	Visibility *Visibility       `json:"visibility"`
+	Properties []CommentProperty `json:"properties"`
+}
+
+func (s *Server) getComments(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	startAt, maxResults := queryInt(r, "startAt", 0), queryInt(r, "maxResults", 50)
+	withProperties := false
+	for _, e := range splitList(r.URL.Query()["expand"]) {
+		withProperties = withProperties || e == "properties"
+	}
+
+	b := page(len(issue.Comments), startAt, maxResults)
+	comments := make([]Comment, 0, b.end-b.start)
+	for _, c := range issue.Comments[b.start:b.end] {
+		if !withProperties {
+			c.Properties = nil
+		}
+		comments = append(comments, c)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(issue.Comments),
+		"comments":   comments,
+	})
+}
+
+func (s *Server) addComment(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	var body commentRequest
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Unexpected character in the request body: "+err.Error())
+		return
+	} else if isEmptyBody(body.Body) {
+		writeFieldErrors(w, map[string]string{"comment": "Comment body can not be empty!"})
+		return
+	}
+
+	now := formatTime(time.Now())
+	c := Comment{
+		Id:         s.newId(),
+		Author:     s.myself,
+		Body:       body.Body,
+		Created:    now,
+		Updated:    now,
+		Visibility: body.Visibility,
+		Properties: body.Properties,
+	}
+	issue.Comments = append(issue.Comments, c)
+
+	writeJSON(w, http.StatusCreated, c)
+}
+
+// Adds a comment through the Jira Service Management API, which is public unless specified otherwise
+func (s *Server) addServiceDeskComment(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	body := struct {
+		Body   string `json:"body"`
+		Public *bool  `json:"public"`
+	}{}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Unexpected character in the request body: "+err.Error())
+		return
+	} else if body.Body == "" {
+		writeError(w, http.StatusBadRequest, "The comment body can not be empty.")
+		return
+	}
+
+	public := body.Public == nil || *body.Public
+	text, _ := json.Marshal(body.Body)
+	now := time.Now()
+	c := Comment{
+		Id:      s.newId(),
+		Author:  s.myself,
+		Body:    text,
+		Created: formatTime(now),
+		Updated: formatTime(now),
+		Public:  &public,
+	}
+	issue.Comments = append(issue.Comments, c)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":     c.Id,
+		"body":   body.Body,
+		"public": public,
+		"author": c.Author,
+		"created": map[string]interface{}{
+			"iso8601":     now.Format("2006-01-02T15:04:05-0700"),
+			"epochMillis": now.UnixNano() / int64(time.Millisecond),
+		},
+	})
+}
+
+func (s *Server) getComment(w http.ResponseWriter, r *http.Request, vars []string) {
+	if issue := s.requestIssue(w, vars[0]); issue != nil {
+		if index := s.requestComment(w, issue, vars[1]); index >= 0 {
+			writeJSON(w, http.StatusOK, issue.Comments[index])
+		}
+	}
+}
+
+func (s *Server) updateComment(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+	index := s.requestComment(w, issue, vars[1])
+	if index < 0 {
+		return
+	}
+
+	var body commentRequest
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Unexpected character in the request body: "+err.Error())
+		return
+	} else if isEmptyBody(body.Body) {
+		writeFieldErrors(w, map[string]string{"comment": "Comment body can not be empty!"})
+		return
+	}
+
+	c := &issue.Comments[index]
+	c.Body = body.Body
+	c.Updated = formatTime(time.Now())
+	if body.Visibility != nil {
+		c.Visibility = body.Visibility
+	}
+	// Like Jira, the properties of the comment are replaced by the ones of the request
+	c.Properties = body.Properties
+
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (s *Server) deleteComment(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	if index := s.requestComment(w, issue, vars[1]); index >= 0 {
+		issue.Comments = append(issue.Comments[:index], issue.Comments[index+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Sets a property of a comment, which is found by its id whatever its issue
+func (s *Server) setCommentProperty(w http.ResponseWriter, r *http.Request, vars []string) {
+	var c *Comment
+	for _, issue := range s.issues {
+		for i := range issue.Comments {
+			if issue.Comments[i].Id == vars[0] {
+				c = &issue.Comments[i]
+			}
+		}
+	}
+	if c == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Can not find a comment for the id: %s.", vars[0]))
+		return
+	}
+
+	var value json.RawMessage
+	if err := decodeBody(r, &value); err != nil {
+		writeError(w, http.StatusBadRequest, "The property value must be valid JSON.")
+		return
+	}
+
+	for i := range c.Properties {
+		if c.Properties[i].Key == vars[1] {
+			c.Properties[i].Value = value
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+	c.Properties = append(c.Properties, CommentProperty{Key: vars[1], Value: value})
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Returns the index of the comment of the request, or writes a 404 and returns -1 when it doesn't exist
+func (s *Server) requestComment(w http.ResponseWriter, issue *Issue, id string) int {
+	for i, c := range issue.Comments {
+		if c.Id == id {
+			return i
+		}
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("Can not find a comment for the id: %s.", id))
+	return -1
+}
+
+func isEmptyBody(body json.RawMessage) bool {
+	s := string(body)
+	return s == "" || s == "null" || s == `""`
+}
@@ -0,0 +1,647 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fields that are computed by the server, they can't be set when editing or creating an issue
+var readOnlyFields = map[string]bool{"status": true, "created": true, "attachment": true}
+
+// Stores the issue, replacing the issue with the same key if any, and returns it with the defaults it was given.
+func (s *Server) AddIssue(issue Issue) Issue {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if issue.Id == "" {
+		issue.Id = s.newId()
+	}
+	if issue.Project == "" {
+		issue.Project = strings.SplitN(issue.Key, "-", 2)[0]
+	}
+	if issue.IssueType == "" {
+		issue.IssueType = "Task"
+	}
+	if issue.Status == "" {
+		issue.Status = s.workflow.InitialStatus
+	}
+	if issue.Created.IsZero() {
+		issue.Created = time.Now()
+	}
+	stored := issue.clone()
+
+	for i, existing := range s.issues {
+		if existing.Key == issue.Key {
+			s.issues[i] = &stored
+			return stored.clone()
+		}
+	}
+	s.issues = append(s.issues, &stored)
+
+	return stored.clone()
+}
+
+// Returns a copy of the issue with the key or the id
+func (s *Server) Issue(key string) (Issue, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if issue := s.findIssue(key); issue != nil {
+		return issue.clone(), true
+	}
+
+	return Issue{}, false
+}
+
+// Changes the key of an issue, as when it is moved to another project. The issue is still found with its former key.
+func (s *Server) MoveIssue(from, to string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if issue := s.findIssue(from); issue != nil {
+		for _, a := range s.attachments {
+			if a.Issue == issue.Key {
+				a.Issue = to
+			}
+		}
+
+		// An issue moved back to its former key isn't redirected anymore
+		delete(s.moved, to)
+		s.moved[issue.Key] = to
+		issue.Key = to
+		issue.Project = strings.SplitN(to, "-", 2)[0]
+	}
+}
+
+// Adds a custom field and returns its id (i.e. 'customfield_10001')
+func (s *Server) AddCustomField(name string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id := "customfield_" + s.newId()
+	s.fields = append(s.fields, Field{Id: id, Name: name, Custom: true})
+
+	return id
+}
+
+// Replaces the workflow of every issue. The issues keep their current status.
+func (s *Server) SetWorkflow(wf Workflow) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.workflow = wf
+}
+
+// Sets the user the requests are sent as: the author of the comments and the user returned by /myself
+func (s *Server) SetMyself(u User) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.myself = u
+}
+
+// Returns the issue with the key, the former key or the id. Must be called with the mutex held.
+func (s *Server) findIssue(key string) *Issue {
+	for moved, ok := s.moved[key]; ok; moved, ok = s.moved[key] {
+		key = moved
+	}
+
+	for _, issue := range s.issues {
+		if issue.Key == key || issue.Id == key {
+			return issue
+		}
+	}
+
+	return nil
+}
+
+// Returns the issue of the request, or writes a 404 when it doesn't exist
+func (s *Server) requestIssue(w http.ResponseWriter, key string) *Issue {
+	issue := s.findIssue(key)
+	if issue == nil {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+	}
+
+	return issue
+}
+
+func (s *Server) fieldName(id string) string {
+	for _, f := range s.fields {
+		if f.Id == id {
+			return f.Name
+		}
+	}
+
+	return ""
+}
+
+// Returns the issue as returned by Jira, with the requested fields (every field when the list is empty or is '*all')
+// and expansions (names, changelog).
+func (s *Server) issueJSON(issue *Issue, fieldList []string, expand []string) map[string]interface{} {
+	all := map[string]interface{}{
+		"summary":   issue.Summary,
+		"status":    map[string]string{"name": issue.Status},
+		"issuetype": map[string]string{"name": issue.IssueType},
+		"project":   map[string]string{"key": issue.Project},
+		"created":   formatTime(issue.Created),
+	}
+
+	// Like Jira, every field is returned, null when it has no value
+	for _, f := range s.fields {
+		if _, ok := all[f.Id]; !ok && f.Id != "parent" {
+			all[f.Id] = nil
+		}
+	}
+	for k, v := range issue.Fields {
+		all[k] = v
+	}
+	all["attachment"] = s.issueAttachments(issue.Key)
+	if parent := s.findIssue(issue.Parent); parent != nil {
+		all["parent"] = map[string]interface{}{
+			"id":  parent.Id,
+			"key": parent.Key,
+			"fields": map[string]interface{}{
+				"summary": parent.Summary,
+				"status":  map[string]string{"name": parent.Status},
+			},
+		}
+	}
+
+	fields := filterFields(all, fieldList)
+	result := map[string]interface{}{
+		"id":     issue.Id,
+		"key":    issue.Key,
+		"self":   s.self("/issue/" + issue.Id),
+		"fields": fields,
+	}
+
+	for _, e := range expand {
+		switch e {
+		case "names":
+			result["names"] = s.names(fields)
+		case "changelog":
+			histories := issue.Histories
+			if s.changelogLimit > 0 && len(histories) > s.changelogLimit {
+				histories = histories[:s.changelogLimit]
+			}
+			result["changelog"] = map[string]interface{}{
+				"startAt":    0,
+				"maxResults": len(histories),
+				"total":      len(issue.Histories),
+				"histories":  histories,
+			}
+		}
+	}
+
+	return result
+}
+
+// Returns the names of the fields, by id
+func (s *Server) names(fields map[string]interface{}) map[string]string {
+	names := make(map[string]string)
+	for id := range fields {
+		if name := s.fieldName(id); name != "" {
+			names[id] = name
+		}
+	}
+
+	return names
+}
+
+// Keeps the fields of the list. The '*all' and '*navigable' values keep every field, and a field prefixed by '-' is
+// removed.
+func filterFields(all map[string]interface{}, fieldList []string) map[string]interface{} {
+	keep, exclude := make(map[string]bool), make(map[string]bool)
+	keepAll := true
+	for _, f := range fieldList {
+		switch {
+		case f == "" || f == "*all" || f == "*navigable":
+		case strings.HasPrefix(f, "-"):
+			exclude[f[1:]] = true
+		default:
+			keep[f] = true
+			keepAll = false
+		}
+	}
+
+	fields := make(map[string]interface{})
+	for k, v := range all {
+		if (keepAll || keep[k]) && !exclude[k] {
+			fields[k] = v
+		}
+	}
+
+	return fields
+}
+
+// Splits a comma separated query parameter
+func splitList(values []string) []string {
+	var list []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
+
+// Returns an integer parameter of the query, or the default value when it is not set or invalid
+func queryInt(r *http.Request, name string, def int) int {
+	if v, err := strconv.Atoi(r.URL.Query().Get(name)); err == nil && v >= 0 {
+		return v
+	}
+
+	return def
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, r *http.Request, vars []string) {
+	if issue := s.requestIssue(w, vars[0]); issue != nil {
+		query := r.URL.Query()
+		writeJSON(w, http.StatusOK, s.issueJSON(issue, splitList(query["fields"]), splitList(query["expand"])))
+	}
+}
+
+func (s *Server) editIssue(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	var body struct {
+		Fields map[string]interface{}   `json:"fields"`
+		Update map[string][]interface{} `json:"update"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Unexpected character in the request body: "+err.Error())
+		return
+	} else if len(body.Update) > 0 {
+		writeError(w, http.StatusBadRequest, "jiratest: the 'update' operations are not supported, set the 'fields' instead")
+		return
+	}
+
+	if errors := s.validateFields(body.Fields, false); len(errors) > 0 {
+		writeFieldErrors(w, errors)
+		return
+	}
+
+	var items []HistoryItem
+	for _, id := range sortedKeys(body.Fields) {
+		value := body.Fields[id]
+		item := HistoryItem{Field: s.fieldName(id), FieldType: "jira", To: fmt.Sprint(value), ToString: fmt.Sprint(value)}
+		if strings.HasPrefix(id, "customfield_") {
+			item.FieldType = "custom"
+		}
+
+		switch id {
+		case "summary":
+			item.FromString = issue.Summary
+			issue.Summary = fmt.Sprint(value)
+		case "parent":
+			item.FromString = issue.Parent
+			issue.Parent = keyOf(value)
+			item.ToString = issue.Parent
+		default:
+			if previous, ok := issue.Fields[id]; ok {
+				item.FromString = fmt.Sprint(previous)
+			}
+			if issue.Fields == nil {
+				issue.Fields = make(map[string]interface{})
+			}
+			issue.Fields[id] = value
+		}
+		items = append(items, item)
+	}
+	s.addHistory(issue, items...)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Returns the errors, by field id, of the fields of an edition or a creation
+func (s *Server) validateFields(fields map[string]interface{}, create bool) map[string]string {
+	errors := make(map[string]string)
+	for id, value := range fields {
+		switch {
+		case readOnlyFields[id] || s.fieldName(id) == "" || (!create && (id == "project" || id == "issuetype")):
+			errors[id] = fmt.Sprintf("Field '%s' cannot be set. It is not on the appropriate screen, or unknown.", id)
+		case id == "summary" && fmt.Sprint(value) == "":
+			errors[id] = "You must specify a summary of the issue."
+		case id == "parent" && s.findIssue(keyOf(value)) == nil:
+			errors[id] = "Could not find issue by id or key."
+		}
+	}
+
+	return errors
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request, vars []string) {
+	var body struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Unexpected character in the request body: "+err.Error())
+		return
+	}
+
+	issue, errors := s.create(body.Fields)
+	if len(errors) > 0 {
+		writeFieldErrors(w, errors)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s.createdJSON(issue))
+}
+
+// Creates every issue it can, like Jira does, and reports the failed ones by their index in the request
+func (s *Server) createIssues(w http.ResponseWriter, r *http.Request, vars []string) {
+	var body struct {
+		IssueUpdates []struct {
+			Fields map[string]interface{} `json:"fields"`
+		} `json:"issueUpdates"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Unexpected character in the request body: "+err.Error())
+		return
+	}
+
+	created := make([]map[string]string, 0)
+	failed := make([]map[string]interface{}, 0)
+	for i, update := range body.IssueUpdates {
+		issue, errors := s.create(update.Fields)
+		if len(errors) > 0 {
+			failed = append(failed, map[string]interface{}{
+				"status":              http.StatusBadRequest,
+				"elementErrors":       ErrorCollection{ErrorMessages: []string{}, Errors: errors},
+				"failedElementNumber": i,
+			})
+			continue
+		}
+		created = append(created, s.createdJSON(issue))
+	}
+
+	status := http.StatusCreated
+	if len(created) == 0 && len(failed) > 0 {
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, map[string]interface{}{"issues": created, "errors": failed})
+}
+
+// Creates an issue from the fields of a creation request, or returns the errors by field id
+func (s *Server) create(fields map[string]interface{}) (*Issue, map[string]string) {
+	errors := s.validateFields(fields, true)
+	project := keyOf(fields["project"])
+	if project == "" {
+		errors["project"] = "project is required"
+	}
+	if _, ok := fields["summary"]; !ok {
+		errors["summary"] = "You must specify a summary of the issue."
+	}
+	issueType, _ := fields["issuetype"].(map[string]interface{})
+	if issueType == nil || issueType["name"] == nil {
+		errors["issuetype"] = "issue type is required"
+	}
+	if len(errors) > 0 {
+		return nil, errors
+	}
+
+	issue := &Issue{
+		Id:        s.newId(),
+		Key:       fmt.Sprintf("%s-%d", project, s.nextNumber(project)),
+		Project:   project,
+		IssueType: fmt.Sprint(issueType["name"]),
+		Summary:   fmt.Sprint(fields["summary"]),
+		Status:    s.workflow.InitialStatus,
+		Parent:    keyOf(fields["parent"]),
+		Created:   time.Now(),
+		Fields:    make(map[string]interface{}),
+	}
+	for id, value := range fields {
+		switch id {
+		case "project", "issuetype", "summary", "parent":
+		default:
+			issue.Fields[id] = value
+		}
+	}
+	s.issues = append(s.issues, issue)
+
+	return issue, nil
+}
+
+func (s *Server) createdJSON(issue *Issue) map[string]string {
+	return map[string]string{"id": issue.Id, "key": issue.Key, "self": s.self("/issue/" + issue.Id)}
+}
+
+// Returns the number of the next issue of the project
+func (s *Server) nextNumber(project string) int {
+	next := 1
+	for _, issue := range s.issues {
+		if issue.Project != project {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimPrefix(issue.Key, project+"-")); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+
+	return next
+}
+
+// Returns the key of a reference to an issue or a project ({"key": "ABC"}), or the value itself when it is a string
+func keyOf(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		if key, ok := v["key"].(string); ok {
+			return key
+		}
+	}
+
+	return ""
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Adds an entry to the changelog of the issue. Must be called with the mutex held.
+func (s *Server) addHistory(issue *Issue, items ...HistoryItem) {
+	if len(items) == 0 {
+		return
+	}
+
+	issue.Histories = append(issue.Histories, History{
+		Id:      s.newId(),
+		Author:  s.myself,
+		Created: formatTime(time.Now()),
+		Items:   items,
+	})
+}
+
+func (s *Server) getChangelog(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	startAt, maxResults := queryInt(r, "startAt", 0), queryInt(r, "maxResults", 100)
+	values := page(len(issue.Histories), startAt, maxResults)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(issue.Histories),
+		"isLast":     values.end == len(issue.Histories),
+		"values":     append([]History{}, issue.Histories[values.start:values.end]...),
+	})
+}
+
+type bounds struct{ start, end int }
+
+// Returns the bounds of a page of a list of 'total' elements
+func page(total, startAt, maxResults int) bounds {
+	b := bounds{start: startAt, end: startAt + maxResults}
+	if b.start > total {
+		b.start = total
+	}
+	if b.end > total {
+		b.end = total
+	}
+
+	return b
+}
+
+func (s *Server) getTransitions(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	transitions := make([]map[string]interface{}, 0)
+	for _, t := range s.workflow.available(issue.Status) {
+		transitions = append(transitions, map[string]interface{}{
+			"id":   t.Id,
+			"name": t.Name,
+			"to":   map[string]string{"name": t.To},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"expand": "transitions", "transitions": transitions})
+}
+
+func (s *Server) doTransition(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	var body struct {
+		Transition struct {
+			Id string `json:"id"`
+		} `json:"transition"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Unexpected character in the request body: "+err.Error())
+		return
+	}
+
+	for _, t := range s.workflow.available(issue.Status) {
+		if t.Id == body.Transition.Id {
+			s.addHistory(issue, HistoryItem{Field: "status", FieldType: "jira", FromString: issue.Status, ToString: t.To})
+			issue.Status = t.To
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	writeError(w, http.StatusBadRequest, fmt.Sprintf("Transition id '%s' is not valid for this issue.", body.Transition.Id))
+}
+
+func (s *Server) getPropertyKeys(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	keys := make([]map[string]string, 0, len(issue.Properties))
+	for key := range issue.Properties {
+		keys = append(keys, map[string]string{"key": key, "self": s.self("/issue/" + issue.Id + "/properties/" + key)})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i]["key"] < keys[j]["key"] })
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"keys": keys})
+}
+
+func (s *Server) getProperty(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	value, ok := issue.Properties[vars[1]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("The property with key '%s' does not exist.", vars[1]))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"key": vars[1], "value": value})
+}
+
+func (s *Server) setProperty(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	var value json.RawMessage
+	if err := decodeBody(r, &value); err != nil {
+		writeError(w, http.StatusBadRequest, "The property value must be valid JSON.")
+		return
+	}
+
+	status := http.StatusOK
+	if _, ok := issue.Properties[vars[1]]; !ok {
+		status = http.StatusCreated
+	}
+	if issue.Properties == nil {
+		issue.Properties = make(map[string]json.RawMessage)
+	}
+	issue.Properties[vars[1]] = value
+
+	w.WriteHeader(status)
+}
+
+func (s *Server) deleteProperty(w http.ResponseWriter, r *http.Request, vars []string) {
+	issue := s.requestIssue(w, vars[0])
+	if issue == nil {
+		return
+	}
+
+	if _, ok := issue.Properties[vars[1]]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("The property with key '%s' does not exist.", vars[1]))
+		return
+	}
+	delete(issue.Properties, vars[1])
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getMyself(w http.ResponseWriter, r *http.Request, vars []string) {
+	writeJSON(w, http.StatusOK, s.myself)
+}
+
+func (s *Server) getFields(w http.ResponseWriter, r *http.Request, vars []string) {
+	writeJSON(w, http.StatusOK, s.fields)
+}
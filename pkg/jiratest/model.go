@@ -0,0 +1,211 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"time"
+)
+
+// This struct is an issue stored by the server. Only the key is required to seed an issue: the id, the project (the
+// prefix of the key), the issue type ('Task'), the status (the initial status of the workflow) and the creation date
+// (now) are set when left empty.
+type Issue struct {
+	Id        string
+	Key       string
+	Project   string
+	IssueType string
+	Summary   string
+	Status    string
+	Parent    string
+	Created   time.Time
+
+	// The other fields by id (i.e. 'description', 'labels' or 'customfield_10010'), as they are sent and returned
+	Fields map[string]interface{}
+
+	Properties map[string]json.RawMessage
+	Comments   []Comment
+	Histories  []History
+}
+
+// This struct is a field of the Jira instance. Values of custom fields are stored on the issues by id.
+type Field struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	Custom bool   `json:"custom"`
+}
+
+// This struct is a Jira user, as returned in the author of a comment or by /myself.
+type User struct {
+	AccountId    string `json:"accountId,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Key          string `json:"key,omitempty"`
+	DisplayName  string `json:"displayName,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+// This struct is a comment of an issue. The body is kept as it was sent: a string with the version 2 of the API and an
+// Atlassian Document Format object with the version 3. Public is only set on the comments posted through the Jira
+// Service Management API.
+type Comment struct {
+	Id         string            `json:"id"`
+	Author     User              `json:"author"`
+	Body       json.RawMessage   `json:"body"`
+	Created    string            `json:"created"`
+	Updated    string            `json:"updated"`
+	Visibility *Visibility       `json:"visibility,omitempty"`
+	Properties []CommentProperty `json:"properties,omitempty"`
+	Public     *bool             `json:"jsdPublic,omitempty"`
+}
+
+type Visibility struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type CommentProperty struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// This struct is an entry of the changelog of an issue. The server adds one on each transition and each edit.
+type History struct {
+	Id      string        `json:"id"`
+	Author  User          `json:"author"`
+	Created string        `json:"created"`
+	Items   []HistoryItem `json:"items"`
+}
+
+type HistoryItem struct {
+	Field      string `json:"field"`
+	FieldType  string `json:"fieldtype"`
+	From       string `json:"from"`
+	FromString string `json:"fromString"`
+	To         string `json:"to"`
+	ToString   string `json:"toString"`
+}
+
+// This struct is a version of a project.
+type Version struct {
+	Id          string `json:"id"`
+	Self        string `json:"self,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Project     string `json:"project"`
+	Released    bool   `json:"released"`
+	Archived    bool   `json:"archived"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+}
+
+// This struct is a file attached to an issue.
+type Attachment struct {
+	Id       string
+	Issue    string
+	Filename string
+	MimeType string
+	Content  []byte
+	Author   User
+	Created  time.Time
+}
+
+// The Workflow struct defines the transitions available from each status. A transition without any origin status is
+// a global transition, available from every status but its destination.
+type Workflow struct {
+	InitialStatus string
+	Transitions   []WorkflowTransition
+}
+
+type WorkflowTransition struct {
+	Id   string
+	Name string
+	From []string
+	To   string
+}
+
+// Body of the errors returned by Jira
+type ErrorCollection struct {
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+// Returns the classic Jira workflow: Open, In Progress, Resolved, Closed and Reopened.
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		InitialStatus: "Open",
+		Transitions: []WorkflowTransition{
+			{Id: "4", Name: "Start Progress", From: []string{"Open", "Reopened"}, To: "In Progress"},
+			{Id: "301", Name: "Stop Progress", From: []string{"In Progress"}, To: "Open"},
+			{Id: "5", Name: "Resolve Issue", From: []string{"Open", "In Progress", "Reopened"}, To: "Resolved"},
+			{Id: "2", Name: "Close Issue", From: []string{"Open", "In Progress", "Resolved", "Reopened"}, To: "Closed"},
+			{Id: "3", Name: "Reopen Issue", From: []string{"Resolved", "Closed"}, To: "Reopened"},
+		},
+	}
+}
+
+// Returns the user the requests are sent as, unless another one is set with SetMyself.
+func DefaultUser() User {
+	return User{
+		AccountId:    "5b10a2844c20165700ede21g",
+		Name:         "resource",
+		Key:          "resource",
+		DisplayName:  "Jira API Issue Resource",
+		EmailAddress: "resource@example.com",
+	}
+}
+
+func defaultFields() []Field {
+	return []Field{
+		{Id: "summary", Name: "Summary"},
+		{Id: "description", Name: "Description"},
+		{Id: "status", Name: "Status"},
+		{Id: "issuetype", Name: "Issue Type"},
+		{Id: "project", Name: "Project"},
+		{Id: "parent", Name: "Parent"},
+		{Id: "created", Name: "Created"},
+		{Id: "labels", Name: "Labels"},
+		{Id: "fixVersions", Name: "Fix Version/s"},
+		{Id: "attachment", Name: "Attachment"},
+	}
+}
+
+// Returns the transitions available from the status
+func (wf Workflow) available(status string) []WorkflowTransition {
+	var transitions []WorkflowTransition
+	for _, t := range wf.Transitions {
+		if len(t.From) == 0 {
+			if t.To != status {
+				transitions = append(transitions, t)
+			}
+			continue
+		}
+
+		for _, from := range t.From {
+			if from == status {
+				transitions = append(transitions, t)
+				break
+			}
+		}
+	}
+
+	return transitions
+}
+
+// Returns a copy of the issue that doesn't share any map or slice with it
+func (i *Issue) clone() Issue {
+	c := *i
+	c.Fields = make(map[string]interface{}, len(i.Fields))
+	for k, v := range i.Fields {
+		c.Fields[k] = v
+	}
+	c.Properties = make(map[string]json.RawMessage, len(i.Properties))
+	for k, v := range i.Properties {
+		c.Properties[k] = v
+	}
+	c.Comments = append([]Comment{}, i.Comments...)
+	c.Histories = append([]History{}, i.Histories...)
+
+	return c
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(helpers.JiraTimeLayout)
+}
@@ -0,0 +1,216 @@
+package jiratest
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Maximum number of issues returned by a search, whatever the requested maximum
+const maxSearchResults = 100
+
+var (
+	orderBy   = regexp.MustCompile(`(?i)\s*order\s+by\s+.*$`)
+	and       = regexp.MustCompile(`(?i)\s+and\s+`)
+	jqlClause = regexp.MustCompile(`(?i)^(key|issuekey|project|status|parent|issuetype)\s*(=|\s+in\s+)\s*(.+)$`)
+)
+
+// A clause of the subset of JQL supported by the server: 'field = value' or 'field in (value, ...)'. The clauses can
+// be joined with AND and the ORDER BY statement is ignored.
+type clause struct {
+	field  string
+	values []string
+}
+
+// Body of a search sent as a POST
+type searchRequest struct {
+	Jql           string   `json:"jql"`
+	StartAt       int      `json:"startAt"`
+	MaxResults    *int     `json:"maxResults"`
+	Fields        []string `json:"fields"`
+	Expand        []string `json:"expand"`
+	ValidateQuery string   `json:"validateQuery"`
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request, vars []string) {
+	req := searchRequest{}
+	if r.Method == http.MethodPost {
+		if err := decodeBody(r, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "Unexpected character in the request body: "+err.Error())
+			return
+		}
+	} else {
+		query := r.URL.Query()
+		req.Jql = query.Get("jql")
+		req.StartAt = queryInt(r, "startAt", 0)
+		maxResults := queryInt(r, "maxResults", 50)
+		req.MaxResults = &maxResults
+		req.Fields = splitList(query["fields"])
+		req.Expand = splitList(query["expand"])
+		req.ValidateQuery = query.Get("validateQuery")
+	}
+
+	maxResults := 50
+	if req.MaxResults != nil {
+		maxResults = *req.MaxResults
+	}
+	if maxResults > maxSearchResults {
+		maxResults = maxSearchResults
+	}
+
+	clauses, err := parseJql(req.Jql)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Unknown keys are an error unless the query is validated leniently
+	warnings := s.unknownKeys(clauses)
+	switch strings.ToLower(req.ValidateQuery) {
+	case "warn", "none", "false":
+	default:
+		if len(warnings) > 0 {
+			writeError(w, http.StatusBadRequest, warnings...)
+			return
+		}
+	}
+
+	var matches []*Issue
+	for _, issue := range s.issues {
+		if s.matchesAll(issue, clauses) {
+			matches = append(matches, issue)
+		}
+	}
+
+	b := page(len(matches), req.StartAt, maxResults)
+	issues := make([]map[string]interface{}, 0, b.end-b.start)
+	names := make(map[string]string)
+	for _, issue := range matches[b.start:b.end] {
+		i := s.issueJSON(issue, req.Fields, req.Expand)
+		if n, ok := i["names"].(map[string]string); ok {
+			for k, v := range n {
+				names[k] = v
+			}
+			delete(i, "names")
+		}
+		issues = append(issues, i)
+	}
+
+	result := map[string]interface{}{
+		"expand":     "names,schema",
+		"startAt":    req.StartAt,
+		"maxResults": maxResults,
+		"total":      len(matches),
+		"issues":     issues,
+	}
+	for _, e := range req.Expand {
+		if e == "names" {
+			result["names"] = names
+		}
+	}
+	if len(warnings) > 0 && strings.ToLower(req.ValidateQuery) == "warn" {
+		result["warningMessages"] = warnings
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Parses the JQL query. An empty query matches every issue.
+func parseJql(jql string) ([]clause, error) {
+	jql = strings.TrimSpace(orderBy.ReplaceAllString(jql, ""))
+	if jql == "" {
+		return nil, nil
+	}
+
+	var clauses []clause
+	for _, part := range and.Split(jql, -1) {
+		m := jqlClause.FindStringSubmatch(strings.TrimSpace(part))
+		if m == nil {
+			return nil, fmt.Errorf("Error in the JQL Query: jiratest doesn't support the clause '%s'.", part)
+		}
+
+		c := clause{field: strings.ToLower(m[1])}
+		if c.field == "issuekey" {
+			c.field = "key"
+		}
+
+		value := strings.TrimSpace(m[3])
+		if strings.TrimSpace(m[2]) == "=" {
+			c.values = []string{unquote(value)}
+		} else if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+			for _, v := range strings.Split(value[1:len(value)-1], ",") {
+				c.values = append(c.values, unquote(strings.TrimSpace(v)))
+			}
+		} else {
+			return nil, fmt.Errorf("Error in the JQL Query: expecting '(' before '%s'.", value)
+		}
+		clauses = append(clauses, c)
+	}
+
+	return clauses, nil
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+// Returns a message for each key of the clauses that doesn't match any issue
+func (s *Server) unknownKeys(clauses []clause) []string {
+	var messages []string
+	for _, c := range clauses {
+		if c.field != "key" {
+			continue
+		}
+		for _, key := range c.values {
+			if s.findIssue(key) == nil {
+				messages = append(messages, fmt.Sprintf("An issue with key '%s' does not exist for field 'key'.", key))
+			}
+		}
+	}
+
+	return messages
+}
+
+func (s *Server) matchesAll(issue *Issue, clauses []clause) bool {
+	for _, c := range clauses {
+		if !s.matches(issue, c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Server) matches(issue *Issue, c clause) bool {
+	for _, v := range c.values {
+		switch c.field {
+		case "key":
+			if s.findIssue(v) == issue {
+				return true
+			}
+		case "parent":
+			if parent := s.findIssue(v); parent != nil && parent == s.findIssue(issue.Parent) {
+				return true
+			}
+		case "project":
+			if strings.EqualFold(issue.Project, v) {
+				return true
+			}
+		case "status":
+			if strings.EqualFold(issue.Status, v) {
+				return true
+			}
+		case "issuetype":
+			if strings.EqualFold(issue.IssueType, v) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
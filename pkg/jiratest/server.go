@@ -0,0 +1,319 @@
+// Package jiratest provides an in-memory fake of the Jira REST API, served by an httptest.Server. It implements the
+// subset of the API used by the resource (issues with the names expansion, fields, transitions and workflow, comments,
+// issue properties, search, versions and attachments) so that the services and the pipeline can be tested against the
+// requests they actually send. Errors and latency can be injected to exercise the retries and the timeouts.
+//
+// The server is seeded through its methods (AddIssue, AddCustomField, SetWorkflow, ...) and every request it served
+// can be inspected afterwards with Requests.
+package jiratest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Matches the prefix of the Jira REST API (the version 2 and 3 are served the same way)
+var apiPrefix = regexp.MustCompile(`^/rest/api/[23]`)
+
+// The Server struct is an in-memory Jira instance. Its zero value is not usable, it is created with NewServer and
+// must be closed once the test is over.
+type Server struct {
+	*httptest.Server
+
+	mutex       sync.Mutex
+	issues      []*Issue
+	moved       map[string]string
+	fields      []Field
+	workflow    Workflow
+	myself      User
+	versions    []*Version
+	attachments []*Attachment
+	nextId      int
+
+	// Maximum number of histories embedded in an issue read with expand=changelog, every one when 0
+	changelogLimit int
+
+	requests []Request
+	faults   []*Fault
+	hooks    []Hook
+	latency  time.Duration
+}
+
+// This struct is a request served by the server. The path doesn't include the /rest/api/{version} prefix.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   []byte
+}
+
+// A Fault makes the server answer the matching requests with an error instead of handling them.
+type Fault struct {
+	// Method of the matching requests, any method when empty
+	Method string
+
+	// Prefix of the path (without the /rest/api/{version} prefix) of the matching requests, any path when empty
+	Path string
+
+	// Status of the response and its body (a Jira error message when empty)
+	Status int
+	Body   string
+
+	// Headers of the response, such as Retry-After
+	Header http.Header
+
+	// Number of requests answered with the error, every matching request when 0
+	Times int
+}
+
+// A Hook is called before each request is handled. It returns true when it wrote the response itself, in which case
+// the request is not handled by the server.
+type Hook func(w http.ResponseWriter, r *http.Request) bool
+
+type route struct {
+	method  string
+	pattern []string
+	handler func(w http.ResponseWriter, r *http.Request, vars []string)
+}
+
+// Creates and starts a server with the default fields and workflow, and without any issue.
+func NewServer() *Server {
+	s := &Server{
+		moved:    make(map[string]string),
+		fields:   defaultFields(),
+		workflow: DefaultWorkflow(),
+		myself:   DefaultUser(),
+		nextId:   10000,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	return s
+}
+
+// Returns the URL of the version 2 of the REST API, to be used as the base URL of the client
+func (s *Server) APIURL() string {
+	return s.URL + "/rest/api/2"
+}
+
+// Makes the server answer the requests matching the fault with an error
+func (s *Server) InjectFault(f Fault) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// Delays every response of the server by the duration
+func (s *Server) SetLatency(d time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.latency = d
+}
+
+// Limits the number of histories embedded in an issue read with expand=changelog, as Jira Cloud does. The remaining
+// ones can only be read through the /issue/{key}/changelog endpoint.
+func (s *Server) SetChangelogLimit(limit int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.changelogLimit = limit
+}
+
+// Adds a hook called before each request is handled, after the faults
+func (s *Server) OnRequest(hook Hook) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.hooks = append(s.hooks, hook)
+}
+
+// Returns every request served so far, in the order they were received
+func (s *Server) Requests() []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]Request{}, s.requests...)
+}
+
+// Returns the requests served so far with the method and the path (without the query)
+func (s *Server) RequestsTo(method, path string) []Request {
+	var requests []Request
+	for _, r := range s.Requests() {
+		if r.Method == method && r.Path == path {
+			requests = append(requests, r)
+		}
+	}
+
+	return requests
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	path := apiPrefix.ReplaceAllString(r.URL.Path, "")
+
+	s.mutex.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: path, Query: r.URL.Query(), Body: body})
+	latency := s.latency
+	fault := s.matchFault(r.Method, path)
+	hooks := append([]Hook{}, s.hooks...)
+	s.mutex.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fault != nil {
+		for k, values := range fault.Header {
+			w.Header()[k] = values
+		}
+		if fault.Body == "" {
+			writeError(w, fault.Status, fmt.Sprintf("Injected error (HTTP %d)", fault.Status))
+		} else {
+			w.WriteHeader(fault.Status)
+			_, _ = w.Write([]byte(fault.Body))
+		}
+		return
+	}
+
+	for _, hook := range hooks {
+		if hook(w, r) {
+			return
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, rt := range s.routes() {
+		if vars, ok := rt.match(r.Method, segments); ok {
+			rt.handler(w, r, vars)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("jiratest: no handler for %s %s", r.Method, path))
+}
+
+// Returns the first fault matching the request and counts the request against it. Must be called with the mutex held.
+func (s *Server) matchFault(method, path string) *Fault {
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != method) || !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+
+	return nil
+}
+
+func (s *Server) routes() []route {
+	return []route{
+		{http.MethodGet, []string{"myself"}, s.getMyself},
+		{http.MethodGet, []string{"field"}, s.getFields},
+		{http.MethodGet, []string{"search"}, s.search},
+		{http.MethodPost, []string{"search"}, s.search},
+		{http.MethodPost, []string{"issue"}, s.createIssue},
+		{http.MethodPost, []string{"issue", "bulk"}, s.createIssues},
+		{http.MethodGet, []string{"issue", "*"}, s.getIssue},
+		{http.MethodPut, []string{"issue", "*"}, s.editIssue},
+		{http.MethodGet, []string{"issue", "*", "changelog"}, s.getChangelog},
+		{http.MethodGet, []string{"issue", "*", "transitions"}, s.getTransitions},
+		{http.MethodPost, []string{"issue", "*", "transitions"}, s.doTransition},
+		{http.MethodGet, []string{"issue", "*", "comment"}, s.getComments},
+		{http.MethodPost, []string{"issue", "*", "comment"}, s.addComment},
+		{http.MethodGet, []string{"issue", "*", "comment", "*"}, s.getComment},
+		{http.MethodPut, []string{"issue", "*", "comment", "*"}, s.updateComment},
+		{http.MethodDelete, []string{"issue", "*", "comment", "*"}, s.deleteComment},
+		{http.MethodPost, []string{"rest", "servicedeskapi", "request", "*", "comment"}, s.addServiceDeskComment},
+		{http.MethodPut, []string{"comment", "*", "properties", "*"}, s.setCommentProperty},
+		{http.MethodGet, []string{"issue", "*", "properties"}, s.getPropertyKeys},
+		{http.MethodGet, []string{"issue", "*", "properties", "*"}, s.getProperty},
+		{http.MethodPut, []string{"issue", "*", "properties", "*"}, s.setProperty},
+		{http.MethodDelete, []string{"issue", "*", "properties", "*"}, s.deleteProperty},
+		{http.MethodPost, []string{"issue", "*", "attachments"}, s.addAttachments},
+		{http.MethodGet, []string{"attachment", "*"}, s.getAttachment},
+		{http.MethodDelete, []string{"attachment", "*"}, s.deleteAttachment},
+		{http.MethodGet, []string{"secure", "attachment", "*", "*"}, s.getAttachmentContent},
+		{http.MethodGet, []string{"project", "*", "versions"}, s.getVersions},
+		{http.MethodPost, []string{"version"}, s.createVersion},
+		{http.MethodGet, []string{"version", "*"}, s.getVersion},
+		{http.MethodPut, []string{"version", "*"}, s.updateVersion},
+		{http.MethodDelete, []string{"version", "*"}, s.deleteVersion},
+	}
+}
+
+// Returns the values of the wildcards of the pattern when the request matches the route
+func (rt route) match(method string, segments []string) ([]string, bool) {
+	if method != rt.method || len(segments) != len(rt.pattern) {
+		return nil, false
+	}
+
+	var vars []string
+	for i, p := range rt.pattern {
+		if p == "*" {
+			value, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+			vars = append(vars, value)
+		} else if p != segments[i] {
+			return nil, false
+		}
+	}
+
+	return vars, true
+}
+
+// Returns a new id, unique across every entity of the server. Must be called with the mutex held.
+func (s *Server) newId() string {
+	s.nextId++
+	return fmt.Sprint(s.nextId)
+}
+
+func (s *Server) self(path string) string {
+	return s.URL + "/rest/api/2" + path
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// Writes an error the way Jira does: {"errorMessages": [...], "errors": {}}
+func writeError(w http.ResponseWriter, status int, messages ...string) {
+	writeJSON(w, status, ErrorCollection{ErrorMessages: messages, Errors: map[string]string{}})
+}
+
+// Writes errors related to fields of the request, by field id
+func writeFieldErrors(w http.ResponseWriter, errors map[string]string) {
+	writeJSON(w, http.StatusBadRequest, ErrorCollection{ErrorMessages: []string{}, Errors: errors})
+}
+
+func decodeBody(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
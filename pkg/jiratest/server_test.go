@@ -0,0 +1,292 @@
+package jiratest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/jiratest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Sends the request to the server and decodes the JSON response, if any, into the value
+func send(t *testing.T, s *jiratest.Server, method, path, body string, v interface{}) int {
+	req, err := http.NewRequest(method, s.APIURL()+path, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if v != nil {
+		b, _ := ioutil.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(b, v), string(b))
+	}
+
+	return resp.StatusCode
+}
+
+func TestServer_Issues(t *testing.T) {
+	s := jiratest.NewServer()
+	defer s.Close()
+
+	id := s.AddCustomField("Release Notes")
+	s.AddIssue(jiratest.Issue{Key: "ABC-1", Summary: "Parent"})
+	s.AddIssue(jiratest.Issue{Key: "ABC-2", Parent: "ABC-1", Fields: map[string]interface{}{id: "v1"}})
+
+	t.Run("the names of the fields are expanded", func(t *testing.T) {
+		var issue struct {
+			Key    string                     `json:"key"`
+			Fields map[string]json.RawMessage `json:"fields"`
+			Names  map[string]string          `json:"names"`
+		}
+		assert.Equal(t, http.StatusOK, send(t, s, http.MethodGet, "/issue/ABC-2?expand=names", "", &issue))
+		assert.Equal(t, "Release Notes", issue.Names[id])
+		assert.JSONEq(t, `"v1"`, string(issue.Fields[id]))
+		assert.JSONEq(t, `{"name":"Open"}`, string(issue.Fields["status"]))
+		assert.Contains(t, string(issue.Fields["parent"]), `"key":"ABC-1"`)
+	})
+
+	t.Run("only the requested fields are returned", func(t *testing.T) {
+		var issue struct {
+			Fields map[string]json.RawMessage `json:"fields"`
+		}
+		send(t, s, http.MethodGet, "/issue/ABC-2?fields=status", "", &issue)
+		assert.Len(t, issue.Fields, 1)
+	})
+
+	t.Run("unknown fields can't be edited", func(t *testing.T) {
+		var errors jiratest.ErrorCollection
+		status := send(t, s, http.MethodPut, "/issue/ABC-2", `{"fields":{"customfield_1":"x"}}`, &errors)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, errors.Errors, "customfield_1")
+	})
+
+	t.Run("edited fields are stored", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, send(t, s, http.MethodPut, "/issue/ABC-2", `{"fields":{"`+id+`":"v2"}}`, nil))
+		issue, _ := s.Issue("ABC-2")
+		assert.Equal(t, "v2", issue.Fields[id])
+	})
+
+	t.Run("a missing issue is not found", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, send(t, s, http.MethodGet, "/issue/ABC-9", "", nil))
+	})
+
+	t.Run("a moved issue is found with its former key", func(t *testing.T) {
+		s.MoveIssue("ABC-1", "XYZ-1")
+
+		var issue struct {
+			Key string `json:"key"`
+		}
+		assert.Equal(t, http.StatusOK, send(t, s, http.MethodGet, "/issue/ABC-1", "", &issue))
+		assert.Equal(t, "XYZ-1", issue.Key)
+	})
+}
+
+func TestServer_Transitions(t *testing.T) {
+	s := jiratest.NewServer()
+	defer s.Close()
+
+	s.AddIssue(jiratest.Issue{Key: "ABC-1", Status: "Closed"})
+
+	var transitions struct {
+		Transitions []struct {
+			Id string `json:"id"`
+			To struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	send(t, s, http.MethodGet, "/issue/ABC-1/transitions", "", &transitions)
+	require.Len(t, transitions.Transitions, 1)
+	assert.Equal(t, "Reopened", transitions.Transitions[0].To.Name)
+
+	// Closing is not available from the 'Closed' status
+	assert.Equal(t, http.StatusBadRequest, send(t, s, http.MethodPost, "/issue/ABC-1/transitions", `{"transition":{"id":"2"}}`, nil))
+
+	id := transitions.Transitions[0].Id
+	assert.Equal(t, http.StatusNoContent, send(t, s, http.MethodPost, "/issue/ABC-1/transitions", `{"transition":{"id":"`+id+`"}}`, nil))
+
+	issue, _ := s.Issue("ABC-1")
+	assert.Equal(t, "Reopened", issue.Status)
+	if assert.Len(t, issue.Histories, 1) {
+		assert.Equal(t, jiratest.HistoryItem{Field: "status", FieldType: "jira", FromString: "Closed", ToString: "Reopened"}, issue.Histories[0].Items[0])
+	}
+}
+
+func TestServer_Comments(t *testing.T) {
+	s := jiratest.NewServer()
+	defer s.Close()
+
+	s.AddIssue(jiratest.Issue{Key: "ABC-1"})
+
+	for _, body := range []string{`{"body":"first"}`, `{"body":"second","properties":[{"key":"k","value":1}]}`, `{"body":"third"}`} {
+		assert.Equal(t, http.StatusCreated, send(t, s, http.MethodPost, "/issue/ABC-1/comment", body, nil))
+	}
+	assert.Equal(t, http.StatusBadRequest, send(t, s, http.MethodPost, "/issue/ABC-1/comment", `{"body":""}`, nil))
+
+	var page struct {
+		Total    int                `json:"total"`
+		Comments []jiratest.Comment `json:"comments"`
+	}
+	send(t, s, http.MethodGet, "/issue/ABC-1/comment?startAt=1&maxResults=1", "", &page)
+	assert.Equal(t, 3, page.Total)
+	require.Len(t, page.Comments, 1)
+	assert.JSONEq(t, `"second"`, string(page.Comments[0].Body))
+	assert.Empty(t, page.Comments[0].Properties)
+	assert.Equal(t, jiratest.DefaultUser(), page.Comments[0].Author)
+
+	send(t, s, http.MethodGet, "/issue/ABC-1/comment?startAt=1&maxResults=1&expand=properties", "", &page)
+	assert.Len(t, page.Comments[0].Properties, 1)
+
+	assert.Equal(t, http.StatusNoContent, send(t, s, http.MethodDelete, "/issue/ABC-1/comment/"+page.Comments[0].Id, "", nil))
+	issue, _ := s.Issue("ABC-1")
+	assert.Len(t, issue.Comments, 2)
+}
+
+func TestServer_Search(t *testing.T) {
+	s := jiratest.NewServer()
+	defer s.Close()
+
+	s.AddIssue(jiratest.Issue{Key: "ABC-1"})
+	s.AddIssue(jiratest.Issue{Key: "ABC-2", Status: "Closed"})
+	s.AddIssue(jiratest.Issue{Key: "XYZ-1"})
+
+	var results struct {
+		Total  int `json:"total"`
+		Issues []struct {
+			Key string `json:"key"`
+		} `json:"issues"`
+		Names           map[string]string `json:"names"`
+		WarningMessages []string          `json:"warningMessages"`
+	}
+
+	t.Run("clauses are joined with AND", func(t *testing.T) {
+		send(t, s, http.MethodGet, "/search?jql=project%20%3D%20ABC%20AND%20status%20%3D%20%22Closed%22", "", &results)
+		if assert.Equal(t, 1, results.Total) {
+			assert.Equal(t, "ABC-2", results.Issues[0].Key)
+		}
+	})
+
+	t.Run("unknown keys are reported as warnings when the query is validated leniently", func(t *testing.T) {
+		body := `{"jql":"key in (ABC-1, ABC-9)","fields":["status"],"expand":["names"],"validateQuery":"warn"}`
+		assert.Equal(t, http.StatusOK, send(t, s, http.MethodPost, "/search", body, &results))
+		assert.Equal(t, 1, results.Total)
+		assert.Equal(t, map[string]string{"status": "Status"}, results.Names)
+		assert.Equal(t, []string{"An issue with key 'ABC-9' does not exist for field 'key'."}, results.WarningMessages)
+	})
+
+	t.Run("unknown keys are an error by default", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, send(t, s, http.MethodPost, "/search", `{"jql":"key = ABC-9"}`, nil))
+	})
+
+	t.Run("unsupported JQL is an error", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, send(t, s, http.MethodPost, "/search", `{"jql":"summary ~ release"}`, nil))
+	})
+}
+
+func TestServer_Versions(t *testing.T) {
+	s := jiratest.NewServer()
+	defer s.Close()
+
+	var v jiratest.Version
+	assert.Equal(t, http.StatusCreated, send(t, s, http.MethodPost, "/version", `{"name":"1.0.0","project":"ABC"}`, &v))
+	assert.Equal(t, http.StatusBadRequest, send(t, s, http.MethodPost, "/version", `{"name":"1.0.0","project":"ABC"}`, nil))
+	assert.Equal(t, http.StatusOK, send(t, s, http.MethodPut, "/version/"+v.Id, `{"released":true}`, nil))
+
+	var versions []jiratest.Version
+	send(t, s, http.MethodGet, "/project/ABC/versions", "", &versions)
+	if assert.Len(t, versions, 1) {
+		assert.Equal(t, "1.0.0", versions[0].Name)
+		assert.True(t, versions[0].Released)
+	}
+}
+
+func TestServer_Attachments(t *testing.T) {
+	s := jiratest.NewServer()
+	defer s.Close()
+
+	s.AddIssue(jiratest.Issue{Key: "ABC-1"})
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "report.txt")
+	_, _ = part.Write([]byte("all green"))
+	_ = writer.Close()
+
+	upload := func(token string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, s.APIURL()+"/issue/ABC-1/attachments", bytes.NewReader(body.Bytes()))
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		if token != "" {
+			req.Header.Set("X-Atlassian-Token", token)
+		}
+		resp, err := s.Client().Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := upload("")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp = upload("no-check")
+	var attached []struct {
+		Content string `json:"content"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&attached))
+	_ = resp.Body.Close()
+	require.Len(t, attached, 1)
+
+	resp, err := s.Client().Get(attached[0].Content)
+	require.NoError(t, err)
+	content, _ := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, "all green", string(content))
+
+	if attachments := s.Attachments("ABC-1"); assert.Len(t, attachments, 1) {
+		assert.Equal(t, "report.txt", attachments[0].Filename)
+	}
+}
+
+func TestServer_Faults(t *testing.T) {
+	s := jiratest.NewServer()
+	defer s.Close()
+
+	s.AddIssue(jiratest.Issue{Key: "ABC-1"})
+
+	t.Run("an error is injected the requested number of times", func(t *testing.T) {
+		s.InjectFault(jiratest.Fault{Method: http.MethodGet, Path: "/issue/ABC-1", Status: http.StatusServiceUnavailable, Times: 2})
+
+		assert.Equal(t, http.StatusServiceUnavailable, send(t, s, http.MethodGet, "/issue/ABC-1", "", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, send(t, s, http.MethodGet, "/issue/ABC-1", "", nil))
+		assert.Equal(t, http.StatusOK, send(t, s, http.MethodGet, "/issue/ABC-1", "", nil))
+	})
+
+	t.Run("the responses are delayed by the latency", func(t *testing.T) {
+		s.SetLatency(50 * time.Millisecond)
+		defer s.SetLatency(0)
+
+		start := time.Now()
+		send(t, s, http.MethodGet, "/issue/ABC-1", "", nil)
+		assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	})
+
+	t.Run("a hook can answer the request itself", func(t *testing.T) {
+		s.OnRequest(func(w http.ResponseWriter, r *http.Request) bool {
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return true
+			}
+			return false
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, send(t, s, http.MethodGet, "/issue/ABC-1", "", nil))
+	})
+
+	assert.Len(t, s.RequestsTo(http.MethodGet, "/issue/ABC-1"), 5)
+}
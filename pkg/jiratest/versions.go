@@ -0,0 +1,130 @@
+package jiratest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Stores the version and returns it with its id
+func (s *Server) AddVersion(v Version) Version {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if v.Id == "" {
+		v.Id = s.newId()
+	}
+	v.Self = s.self("/version/" + v.Id)
+	s.versions = append(s.versions, &v)
+
+	return v
+}
+
+// Returns the versions of the project
+func (s *Server) Versions(project string) []Version {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	versions := make([]Version, 0)
+	for _, v := range s.versions {
+		if strings.EqualFold(v.Project, project) {
+			versions = append(versions, *v)
+		}
+	}
+
+	return versions
+}
+
+func (s *Server) findVersion(id string) int {
+	for i, v := range s.versions {
+		if v.Id == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Returns the index of the version of the request, or writes a 404 and returns -1 when it doesn't exist
+func (s *Server) requestVersion(w http.ResponseWriter, id string) int {
+	index := s.findVersion(id)
+	if index < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Could not find version for id '%s'", id))
+	}
+
+	return index
+}
+
+func (s *Server) getVersions(w http.ResponseWriter, r *http.Request, vars []string) {
+	versions := make([]Version, 0)
+	for _, v := range s.versions {
+		if strings.EqualFold(v.Project, vars[0]) {
+			versions = append(versions, *v)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, versions)
+}
+
+func (s *Server) createVersion(w http.ResponseWriter, r *http.Request, vars []string) {
+	var v Version
+	if err := decodeBody(r, &v); err != nil {
+		writeError(w, http.StatusBadRequest, "Unexpected character in the request body: "+err.Error())
+		return
+	}
+
+	errors := make(map[string]string)
+	if v.Name == "" {
+		errors["name"] = "You must specify a valid version name"
+	}
+	if v.Project == "" {
+		errors["project"] = "Project must be specified to create a version."
+	}
+	for _, existing := range s.versions {
+		if strings.EqualFold(existing.Project, v.Project) && existing.Name == v.Name {
+			errors["name"] = "A version with this name already exists in this project."
+		}
+	}
+	if len(errors) > 0 {
+		writeFieldErrors(w, errors)
+		return
+	}
+
+	v.Id = s.newId()
+	v.Self = s.self("/version/" + v.Id)
+	s.versions = append(s.versions, &v)
+
+	writeJSON(w, http.StatusCreated, v)
+}
+
+func (s *Server) getVersion(w http.ResponseWriter, r *http.Request, vars []string) {
+	if index := s.requestVersion(w, vars[0]); index >= 0 {
+		writeJSON(w, http.StatusOK, s.versions[index])
+	}
+}
+
+// Updates the version with the fields of the request, the others are left unchanged
+func (s *Server) updateVersion(w http.ResponseWriter, r *http.Request, vars []string) {
+	index := s.requestVersion(w, vars[0])
+	if index < 0 {
+		return
+	}
+
+	update := *s.versions[index]
+	if err := decodeBody(r, &update); err != nil {
+		writeError(w, http.StatusBadRequest, "Unexpected character in the request body: "+err.Error())
+		return
+	}
+	update.Id = s.versions[index].Id
+	update.Self = s.versions[index].Self
+	*s.versions[index] = update
+
+	writeJSON(w, http.StatusOK, update)
+}
+
+func (s *Server) deleteVersion(w http.ResponseWriter, r *http.Request, vars []string) {
+	if index := s.requestVersion(w, vars[0]); index >= 0 {
+		s.versions = append(s.versions[:index], s.versions[index+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
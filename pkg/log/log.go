@@ -2,6 +2,7 @@ package log
 
 import (
 	"log"
+	"sync"
 )
 
 // Based partially on the following links:
@@ -13,6 +14,9 @@ type ResourceLogger struct {
 	Logger *log.Logger
 	Level  int
 	ready  bool
+
+	// The prefix is set before each print, the issues processed concurrently must not interleave the two
+	mutex sync.Mutex
 }
 
 func (rl *ResourceLogger) InitLoggerFromParam(levelStringValue string) {
@@ -33,38 +37,41 @@ func (rl *ResourceLogger) InitLogger(levelValue int) {
 }
 
 func (rl *ResourceLogger) Debug(vals ...interface{}) {
-	rl.log(DEBUG, vals)
+	rl.log(DEBUG, vals...)
 }
 
 func (rl *ResourceLogger) Debugf(format string, vals ...interface{}) {
-	rl.logf(DEBUG, format, vals)
+	rl.logf(DEBUG, format, vals...)
 }
 
 func (rl *ResourceLogger) Info(vals ...interface{}) {
-	rl.log(INFO, vals)
+	rl.log(INFO, vals...)
 }
 
 func (rl *ResourceLogger) Infof(format string, vals ...interface{}) {
-	rl.logf(INFO, format, vals)
+	rl.logf(INFO, format, vals...)
 }
 
 func (rl *ResourceLogger) Warning(vals ...interface{}) {
-	rl.log(WARNING, vals)
+	rl.log(WARNING, vals...)
 }
 
 func (rl *ResourceLogger) Warningf(format string, vals ...interface{}) {
-	rl.logf(WARNING, format, vals)
+	rl.logf(WARNING, format, vals...)
 }
 
 func (rl *ResourceLogger) Error(vals ...interface{}) {
-	rl.log(ERROR, vals)
+	rl.log(ERROR, vals...)
 }
 
 func (rl *ResourceLogger) Errorf(format string, vals ...interface{}) {
-	rl.logf(ERROR, format, vals)
+	rl.logf(ERROR, format, vals...)
 }
 
 func (rl *ResourceLogger) log(level int, vals ...interface{}) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
 	if !rl.ready {
 		rl.InitLogger(INFO)
 	}
@@ -72,11 +79,14 @@ func (rl *ResourceLogger) log(level int, vals ...interface{}) {
 	if level >= rl.Level && rl.Level != OFF {
 		rl.Logger.SetPrefix(GetPrefixForLogger(level))
 
-		rl.Logger.Print(vals)
+		rl.Logger.Print(vals...)
 	}
 }
 
 func (rl *ResourceLogger) logf(level int, format string, vals ...interface{}) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
 	if !rl.ready {
 		rl.InitLogger(INFO)
 	}
@@ -84,6 +94,6 @@ func (rl *ResourceLogger) logf(level int, format string, vals ...interface{}) {
 	if level >= rl.Level && rl.Level != OFF {
 		rl.Logger.SetPrefix(GetPrefixForLogger(level))
 
-		rl.Logger.Printf(format, vals)
+		rl.Logger.Printf(format, vals...)
 	}
 }
@@ -0,0 +1,26 @@
+package markup
+
+import (
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/adf"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+)
+
+// Returns the rich-text value of the text as expected by the targeted API. With the version 3 of the API the text is
+// converted into an Atlassian Document Format document. With the version 2 it is converted into wiki markup if the
+// text is written in Markdown.
+func NewRichText(text string, params configuration.JiraAPIResourceParameters) adf.RichText {
+	if params.IsAPIVersion(configuration.APIVersion3) {
+		return adf.NewRichText(text, true)
+	}
+
+	return adf.NewRichText(ToWiki(text, params), false)
+}
+
+// Returns the text as wiki markup, converting it if the text is written in Markdown.
+func ToWiki(text string, params configuration.JiraAPIResourceParameters) string {
+	if params.CommentFormat != nil && *params.CommentFormat == configuration.FormatMarkdown {
+		return MarkdownToWiki(text)
+	}
+
+	return text
+}
@@ -0,0 +1,176 @@
+// Package markup provides the conversion of the text written by the users (comments, descriptions, rich-text custom
+// fields) into the rich-text representation expected by the targeted Jira API.
+package markup
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	fencedCode    = regexp.MustCompile("^\\s*```\\s*(\\S*)\\s*$")
+	heading       = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listItem      = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	checklistItem = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	blockquote    = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	horizontal    = regexp.MustCompile(`^\s*(-{3,}|\*{3,}|_{3,})\s*$`)
+	tableRow      = regexp.MustCompile(`^\s*\|.*\|\s*$`)
+	tableDivider  = regexp.MustCompile(`^\s*\|?(\s*:?-+:?\s*\|)+\s*(:?-+:?\s*)?$`)
+
+	inlineCode    = regexp.MustCompile("`([^`]+)`")
+	image         = regexp.MustCompile(`!\[[^\]]*\]\(([^)\s]+)\)`)
+	link          = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	bold          = regexp.MustCompile(`(\*\*|__)([^\s*_](?:.*?[^\s])?)(\*\*|__)`)
+	italicStar    = regexp.MustCompile(`(^|[^*\w])\*([^\s*](?:[^*]*?[^\s*])?)\*($|[^*\w])`)
+	strikethrough = regexp.MustCompile(`~~([^~]+)~~`)
+)
+
+// Placeholder used while converting the bold text so that the single asterisk of the wiki markup isn't mistaken for
+// a markdown italic.
+const boldPlaceholder = "\x00"
+
+// Converts a Markdown text into Jira wiki markup. Headings, bold/italic/strikethrough text, inline and fenced code,
+// links, images, nested lists, checklists, blockquotes, horizontal rules and tables are converted. Any other text is
+// left untouched.
+func MarkdownToWiki(text string) string {
+	lines := strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+	result := make([]string, 0, len(lines))
+	lists := make([]listLevel, 0)
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if m := fencedCode.FindStringSubmatch(line); m != nil {
+			lists = lists[:0]
+			result = append(result, codeMacro(m[1]))
+			for i++; i < len(lines) && fencedCode.FindStringSubmatch(lines[i]) == nil; i++ {
+				result = append(result, lines[i])
+			}
+			result = append(result, "{code}")
+			continue
+		}
+
+		if tableRow.MatchString(line) && i+1 < len(lines) && tableDivider.MatchString(lines[i+1]) {
+			lists = lists[:0]
+			result = append(result, tableLine(line, "||"))
+			for i += 2; i < len(lines) && tableRow.MatchString(lines[i]); i++ {
+				result = append(result, tableLine(lines[i], "|"))
+			}
+			i--
+			continue
+		}
+
+		if m := listItem.FindStringSubmatch(line); m != nil {
+			lists = pushListLevel(lists, len(strings.Replace(m[1], "\t", "    ", -1)), m[2])
+			result = append(result, listPrefix(lists)+" "+checklist(convertInline(m[3])))
+			continue
+		}
+
+		lists = lists[:0]
+
+		switch {
+		case horizontal.MatchString(line):
+			result = append(result, "----")
+		case heading.MatchString(line):
+			m := heading.FindStringSubmatch(line)
+			result = append(result, fmt.Sprintf("h%d. %s", len(m[1]), convertInline(m[2])))
+		case blockquote.MatchString(line):
+			result = append(result, "bq. "+convertInline(blockquote.FindStringSubmatch(line)[1]))
+		default:
+			result = append(result, convertInline(line))
+		}
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// listLevel is a level of a (possibly nested) list along with its indentation and wiki markup character
+type listLevel struct {
+	indent int
+	char   string
+}
+
+func pushListLevel(lists []listLevel, indent int, marker string) []listLevel {
+	char := "*"
+	if marker != "-" && marker != "*" && marker != "+" {
+		char = "#"
+	}
+
+	for len(lists) > 0 && lists[len(lists)-1].indent > indent {
+		lists = lists[:len(lists)-1]
+	}
+
+	if len(lists) > 0 && lists[len(lists)-1].indent == indent {
+		lists[len(lists)-1].char = char
+		return lists
+	}
+
+	return append(lists, listLevel{indent: indent, char: char})
+}
+
+func listPrefix(lists []listLevel) string {
+	var sb strings.Builder
+	for _, l := range lists {
+		sb.WriteString(l.char)
+	}
+
+	return sb.String()
+}
+
+func checklist(text string) string {
+	if m := checklistItem.FindStringSubmatch(text); m != nil {
+		if m[1] == " " {
+			return "(x) " + m[2]
+		}
+
+		return "(/) " + m[2]
+	}
+
+	return text
+}
+
+func codeMacro(language string) string {
+	if language == "" {
+		return "{code}"
+	}
+
+	return "{code:" + language + "}"
+}
+
+func tableLine(line, separator string) string {
+	cells := strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|")
+	for index := range cells {
+		cells[index] = convertInline(strings.TrimSpace(cells[index]))
+	}
+
+	return separator + strings.Join(cells, separator) + separator
+}
+
+// Converts the inline elements of a line. Inline code is converted first and left untouched by the other conversions.
+func convertInline(line string) string {
+	var sb strings.Builder
+	last := 0
+
+	for _, m := range inlineCode.FindAllStringSubmatchIndex(line, -1) {
+		sb.WriteString(convertText(line[last:m[0]]))
+		sb.WriteString("{{" + line[m[2]:m[3]] + "}}")
+		last = m[1]
+	}
+	sb.WriteString(convertText(line[last:]))
+
+	return sb.String()
+}
+
+func convertText(text string) string {
+	text = image.ReplaceAllString(text, "!$1!")
+	text = link.ReplaceAllString(text, "[$1|$2]")
+	text = bold.ReplaceAllString(text, boldPlaceholder+"$2"+boldPlaceholder)
+	// Adjacent italic elements share the character separating them, a second pass converts the ones that were skipped
+	for pass := 0; pass < 2; pass++ {
+		text = italicStar.ReplaceAllString(text, "${1}_${2}_${3}")
+	}
+	text = strikethrough.ReplaceAllString(text, "-$1-")
+
+	return strings.Replace(text, boldPlaceholder, "*", -1)
+}
@@ -0,0 +1,38 @@
+package markup_test
+
+import (
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/markup"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMarkdownToWiki(t *testing.T) {
+	cases := []struct {
+		name     string
+		markdown string
+		wiki     string
+	}{
+		{"headings", "# Release\n### Details ###", "h1. Release\nh3. Details"},
+		{"bold and italic", "**bold** and *italic* and _italic_", "*bold* and _italic_ and _italic_"},
+		{"adjacent italics", "*one* *two*", "_one_ _two_"},
+		{"strikethrough", "~~removed~~", "-removed-"},
+		{"inline code is left untouched", "run `make **all**` now", "run {{make **all**}} now"},
+		{"links and images", "[build](https://ci/1) ![logo](https://ci/logo.png)", "[build|https://ci/1] !https://ci/logo.png!"},
+		{"fenced code", "```go\nx := *p\n```", "{code:go}\nx := *p\n{code}"},
+		{"fenced code without language", "```\nls\n```", "{code}\nls\n{code}"},
+		{"nested lists", "- one\n  1. nested\n- two", "* one\n*# nested\n* two"},
+		{"checklists", "- [x] done\n- [ ] todo", "* (/) done\n* (x) todo"},
+		{"blockquote and rule", "> quoted\n---", "bq. quoted\n----"},
+		{"tables", "| Test | Result |\n|------|:------:|\n| api | **ok** |", "||Test||Result||\n|api|*ok*|"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// Act
+			result := markup.MarkdownToWiki(c.markdown)
+
+			// Assert
+			assert.Equal(t, c.wiki, result)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+// Package properties provides the services to store and read issue entity properties. Entity properties hold
+// structured JSON data (build id, git sha, ...) on an issue without requiring a custom field; they are never
+// displayed by Jira.
+package properties
+
+import "encoding/json"
+
+// This struct is a representation of an issue entity property as returned by the
+// /issue/{key}/properties/{propertyKey} endpoint.
+type EntityProperty struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// This struct is the content of the file written in the destination for each issue.
+type PropertyOutput struct {
+	Issue string          `json:"issue"`
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
@@ -0,0 +1,145 @@
+package properties_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/jiratest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/properties"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const buildMetadata = `{"build":42,"sha":"4f2a9c1"}`
+
+func newPropertyParams(issue, key, value, valueFromFile, destination string) configuration.JiraAPIResourceParameters {
+	params := configuration.JiraAPIResourceParameters{ActiveIssue: issue, Destination: &destination}
+	params.IssueProperty.PropertyKey = &key
+	params.IssueProperty.PropertyValue = &value
+	params.IssueProperty.PropertyValueFromFile = &valueFromFile
+
+	return params
+}
+
+func execute(server *jiratest.Server, s service.Service, params configuration.JiraAPIResourceParameters) error {
+	client := &rest.Client{BaseURL: server.APIURL(), HTTPClient: server.Client()}
+	return service.Execute(context.Background(), client, s, params, true)
+}
+
+func readOutput(t *testing.T, destination, issue string) properties.PropertyOutput {
+	b, err := ioutil.ReadFile(destination + "_" + issue + "_property.json")
+	require.NoError(t, err)
+
+	var output properties.PropertyOutput
+	require.NoError(t, json.Unmarshal(b, &output))
+	return output
+}
+
+func TestIssueProperty(t *testing.T) {
+	dir, err := ioutil.TempDir("", "properties")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("a property set on the issue is read back and written in the destination", func(t *testing.T) {
+		// Arrange
+		server := jiratest.NewServer()
+		defer server.Close()
+		server.AddIssue(jiratest.Issue{Key: "ABC-1"})
+		destination := filepath.Join(dir, "set")
+
+		// Act
+		require.NoError(t, execute(server, &properties.ServiceSetIssueProperty{},
+			newPropertyParams("ABC-1", "build-metadata", buildMetadata, "", destination)))
+		require.NoError(t, execute(server, &properties.ServiceReadIssueProperty{},
+			newPropertyParams("ABC-1", "build-metadata", "", "", destination)))
+
+		// Assert
+		assert.Len(t, server.RequestsTo(http.MethodPut, "/issue/ABC-1/properties/build-metadata"), 1)
+		issue, _ := server.Issue("ABC-1")
+		assert.JSONEq(t, buildMetadata, string(issue.Properties["build-metadata"]))
+
+		output := readOutput(t, destination, "ABC-1")
+		assert.Equal(t, "ABC-1", output.Issue)
+		assert.Equal(t, "build-metadata", output.Key)
+		assert.JSONEq(t, buildMetadata, string(output.Value))
+	})
+
+	t.Run("the value is read from a file", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+		server.AddIssue(jiratest.Issue{Key: "ABC-1"})
+		file := filepath.Join(dir, "metadata.json")
+		require.NoError(t, ioutil.WriteFile(file, []byte(buildMetadata), 0644))
+
+		require.NoError(t, execute(server, &properties.ServiceSetIssueProperty{},
+			newPropertyParams("ABC-1", "build-metadata", "", file, "")))
+
+		issue, _ := server.Issue("ABC-1")
+		assert.JSONEq(t, buildMetadata, string(issue.Properties["build-metadata"]))
+	})
+
+	t.Run("an invalid JSON value is never sent", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+		server.AddIssue(jiratest.Issue{Key: "ABC-1"})
+
+		assert.Error(t, execute(server, &properties.ServiceSetIssueProperty{},
+			newPropertyParams("ABC-1", "build-metadata", "{build: 42", "", "")))
+		assert.Empty(t, server.Requests())
+	})
+
+	t.Run("a property that was never set is written with a null value", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+		server.AddIssue(jiratest.Issue{Key: "ABC-1"})
+		destination := filepath.Join(dir, "missing")
+
+		require.NoError(t, execute(server, &properties.ServiceReadIssueProperty{},
+			newPropertyParams("ABC-1", "build-metadata", "", "", destination)))
+
+		output := readOutput(t, destination, "ABC-1")
+		assert.Equal(t, "build-metadata", output.Key)
+		assert.Equal(t, "null", string(output.Value))
+	})
+
+	t.Run("a missing property is told apart from a missing issue whatever the language of the instance", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+		server.AddIssue(jiratest.Issue{Key: "ABC-1"})
+		server.OnRequest(func(w http.ResponseWriter, r *http.Request) bool {
+			if r.URL.Path != "/rest/api/2/issue/ABC-1/properties/build-metadata" {
+				return false
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorMessages":["La clé build-metadata est introuvable."],"errors":{}}`))
+			return true
+		})
+		destination := filepath.Join(dir, "localized")
+
+		require.NoError(t, execute(server, &properties.ServiceReadIssueProperty{},
+			newPropertyParams("ABC-1", "build-metadata", "", "", destination)))
+
+		assert.Len(t, server.RequestsTo(http.MethodGet, "/issue/ABC-1"), 1)
+		assert.Equal(t, "null", string(readOutput(t, destination, "ABC-1").Value))
+	})
+
+	t.Run("reading the property of a missing issue fails", func(t *testing.T) {
+		server := jiratest.NewServer()
+		defer server.Close()
+
+		err := execute(server, &properties.ServiceReadIssueProperty{},
+			newPropertyParams("ABC-1", "build-metadata", "", "", filepath.Join(dir, "no-issue")))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "issue ABC-1 does not exist or is not visible")
+	})
+}
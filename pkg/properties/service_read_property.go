@@ -0,0 +1,137 @@
+package properties
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/log"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/reading"
+	resulthelper "github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/result"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"net/http"
+)
+
+// The ServiceReadIssueProperty struct implements the service.IterativeService and service.ErrorHandlingService
+// interfaces. It reads an entity property of an issue and writes it in the destination. A property that was never set
+// on the issue is written with a null value.
+//
+// Jira answers 404 both for a missing issue and for a missing property, with a message depending on the language of
+// the instance. The issue is then read to tell them apart.
+type ServiceReadIssueProperty struct {
+	issueId     string
+	propertyKey string
+	destination string
+	property    EntityProperty
+
+	checkingIssue bool
+	done          bool
+}
+
+// See service/service.go for details
+func (s *ServiceReadIssueProperty) InitJiraAPI(params configuration.JiraAPIResourceParameters) (rest.JiraAPI, error) {
+	s.issueId = params.ActiveIssue
+	s.propertyKey = helpers.StringPtrValue(params.IssueProperty.PropertyKey)
+	s.destination = helpers.StringPtrValue(params.Destination)
+
+	return service.PreInitJiraAPI(s, params, http.MethodGet)
+}
+
+// See service/service.go for details
+func (s *ServiceReadIssueProperty) Reset() {
+	s.property = EntityProperty{}
+	s.checkingIssue = false
+	s.done = false
+}
+
+// See service/service.go for details
+func (s *ServiceReadIssueProperty) HasNext() bool {
+	return !s.done
+}
+
+// See service/service.go for details
+func (s *ServiceReadIssueProperty) GetResults() map[string]string {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServiceReadIssueProperty) SetResultsFromPrevious(result map[string]string) {
+}
+
+// See service/service.go for details
+func (s *ServiceReadIssueProperty) GetEndpoint(url string) string {
+	if s.checkingIssue {
+		return reading.IssueFieldsURL(url, s.issueId, reading.IssueDataFields)
+	}
+
+	return propertyEndpoint(url, s.issueId, s.propertyKey)
+}
+
+// See service/service.go for details
+func (s *ServiceReadIssueProperty) CreateRequestBody() []byte {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServiceReadIssueProperty) JSONResponseObject() interface{} {
+	if s.checkingIssue {
+		return &reading.Issue{}
+	}
+
+	return &EntityProperty{}
+}
+
+// See service/service.go for details
+func (s *ServiceReadIssueProperty) PostAPICall(result interface{}) error {
+	s.done = true
+
+	if s.checkingIssue {
+		// The issue exists, so the property was never set on it
+		log.Logger.Warningf("The '%s' property is not set on issue %s", s.propertyKey, s.issueId)
+		s.property = EntityProperty{Key: s.propertyKey, Value: json.RawMessage("null")}
+		return nil
+	}
+
+	if property, ok := result.(*EntityProperty); !ok {
+		return errors.New("failed to convert result of type interface{} to property of type properties.EntityProperty")
+	} else {
+		s.property = *property
+	}
+
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServiceReadIssueProperty) HandleAPIError(err *rest.HTTPError) error {
+	if err.StatusCode != http.StatusNotFound {
+		return err
+	}
+
+	if s.checkingIssue {
+		return fmt.Errorf("issue %s does not exist or is not visible: %v", s.issueId, err)
+	}
+
+	// Either the issue or the property is missing, the next call reads the issue
+	s.checkingIssue = true
+	return nil
+}
+
+func (s *ServiceReadIssueProperty) Name() string {
+	return "ServiceReadIssueProperty"
+}
+
+func (s *ServiceReadIssueProperty) ExecuteAsLastStep(params configuration.JiraAPIResourceParameters) error {
+	if s.destination == "" {
+		return nil
+	}
+
+	file, err := resulthelper.CreateDestination(s.destination+"_"+s.issueId+"_property", "json")
+	if err != nil {
+		return errors.New("failed to create destination file")
+	}
+	defer file.Close()
+
+	return json.NewEncoder(file).Encode(PropertyOutput{Issue: s.issueId, Key: s.property.Key, Value: s.property.Value})
+}
@@ -0,0 +1,100 @@
+package properties
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// The ServiceSetIssueProperty struct implements the service.Service interface. It stores a JSON value as an entity
+// property of an issue, replacing the previous value if any.
+type ServiceSetIssueProperty struct {
+	issueId     string
+	propertyKey string
+	value       []byte
+}
+
+// See service/service.go for details
+func (s *ServiceSetIssueProperty) InitJiraAPI(params configuration.JiraAPIResourceParameters) (rest.JiraAPI, error) {
+	s.issueId = params.ActiveIssue
+	s.propertyKey = helpers.StringPtrValue(params.IssueProperty.PropertyKey)
+
+	val, err := s.extractValue(params)
+	if err != nil {
+		return rest.JiraAPI{}, err
+	}
+
+	s.value = val
+
+	return service.PreInitJiraAPI(s, params, http.MethodPut)
+}
+
+// See service/service.go for details
+func (s *ServiceSetIssueProperty) GetResults() map[string]string {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServiceSetIssueProperty) SetResultsFromPrevious(result map[string]string) {
+}
+
+// See service/service.go for details
+func (s *ServiceSetIssueProperty) GetEndpoint(url string) string {
+	return propertyEndpoint(url, s.issueId, s.propertyKey)
+}
+
+// See service/service.go for details
+func (s *ServiceSetIssueProperty) CreateRequestBody() []byte {
+	return s.value
+}
+
+// See service/service.go for details
+func (s *ServiceSetIssueProperty) JSONResponseObject() interface{} {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServiceSetIssueProperty) PostAPICall(result interface{}) error {
+	return nil
+}
+
+func (s *ServiceSetIssueProperty) Name() string {
+	return "ServiceSetIssueProperty"
+}
+
+func (s *ServiceSetIssueProperty) ExecuteAsLastStep(params configuration.JiraAPIResourceParameters) error {
+	return nil
+}
+
+func (s *ServiceSetIssueProperty) extractValue(params configuration.JiraAPIResourceParameters) ([]byte, error) {
+	var val []byte
+
+	if !helpers.IsStringPtrNilOrEmtpy(params.IssueProperty.PropertyValue) {
+		val = []byte(*params.IssueProperty.PropertyValue)
+	} else if !helpers.IsStringPtrNilOrEmtpy(params.IssueProperty.PropertyValueFromFile) {
+		b, err := ioutil.ReadFile(*params.IssueProperty.PropertyValueFromFile)
+		if err != nil {
+			return nil, err
+		}
+		val = b
+	} else {
+		return nil, errors.New("no value received in ServiceSetIssueProperty. A problem must have occured in the validation stage")
+	}
+
+	if !json.Valid(val) {
+		return nil, fmt.Errorf("the value of the '%s' property is not valid JSON", s.propertyKey)
+	}
+
+	return val, nil
+}
+
+func propertyEndpoint(baseUrl, issueId, propertyKey string) string {
+	return fmt.Sprintf("%s/issue/%s/properties/%s", baseUrl, issueId, url.PathEscape(propertyKey))
+}
@@ -1,8 +1,28 @@
 package reading
 
+import (
+	"fmt"
+	"strings"
+)
+
+// Fields the pipeline reads on every issue before executing the steps (status and parent checks)
+var IssueDataFields = []string{"status", "parent", "issuetype"}
+
 type Issue struct {
 	Id     string `json:"id"`
 	Key    string `json:"key"`
 	Fields Fields `json:"fields"`
 	Names  Names  `json:"names"`
 }
+
+// Returns the URL from which the services read an issue, along with the names of its fields
+func IssueURL(url, issueId string) string {
+	return fmt.Sprintf("%s/issue/%s?expand=names", url, issueId)
+}
+
+// Returns the URL from which an issue is read with only some of its fields, along with their names. The issues
+// prefetched through the search API are cached under that URL, so that they are never served to a service reading the
+// whole issue.
+func IssueFieldsURL(url, issueId string, fields []string) string {
+	return fmt.Sprintf("%s/issue/%s?fields=%s&expand=names", url, issueId, strings.Join(fields, ","))
+}
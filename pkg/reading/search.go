@@ -0,0 +1,24 @@
+package reading
+
+import "encoding/json"
+
+// Body of a search request (POST /search)
+type SearchRequest struct {
+	Jql           string   `json:"jql"`
+	StartAt       int      `json:"startAt"`
+	MaxResults    int      `json:"maxResults"`
+	Fields        []string `json:"fields"`
+	Expand        []string `json:"expand,omitempty"`
+	ValidateQuery string   `json:"validateQuery,omitempty"`
+}
+
+// Page of issues returned by a search. The issues are kept as raw objects, along with the names of their fields, so
+// that each can be served as if it was read on its own.
+type SearchResults struct {
+	StartAt         int                          `json:"startAt"`
+	MaxResults      int                          `json:"maxResults"`
+	Total           int                          `json:"total"`
+	Issues          []map[string]json.RawMessage `json:"issues"`
+	Names           json.RawMessage              `json:"names"`
+	WarningMessages []string                     `json:"warningMessages"`
+}
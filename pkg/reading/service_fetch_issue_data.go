@@ -2,7 +2,6 @@ package reading
 
 import (
 	"errors"
-	"fmt"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
@@ -33,7 +32,7 @@ func (s *ServiceFetchIssueData) SetResultsFromPrevious(result map[string]string)
 }
 
 func (s *ServiceFetchIssueData) GetEndpoint(url string) string {
-	return fmt.Sprintf("%s/issue/%s?expand=names", url, s.issueId)
+	return IssueFieldsURL(url, s.issueId, IssueDataFields)
 }
 
 func (s *ServiceFetchIssueData) CreateRequestBody() []byte {
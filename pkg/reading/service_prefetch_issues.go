@@ -0,0 +1,177 @@
+package reading
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const prefetchBatchSize = 50
+
+// Issue keys quoted in the warnings of a search (i.e. An issue with key 'ABC-123' does not exist for field 'key'.)
+var warningKey = regexp.MustCompile(`'([A-Za-z][A-Za-z0-9_]*-[0-9]+)'`)
+
+// The ServicePrefetchIssues struct implements the service.IterativeService interface. It reads the issues of the list
+// by batches through the search API (jql=key in (...)), requesting only the fields the pipeline reads on every issue
+// (IssueDataFields), instead of reading them one by one.
+type ServicePrefetchIssues struct {
+	keys  []string
+	batch int
+
+	issues  map[string][]byte
+	missing map[string]bool
+}
+
+func (s *ServicePrefetchIssues) InitJiraAPI(params configuration.JiraAPIResourceParameters) (rest.JiraAPI, error) {
+	s.keys = params.IssueList
+
+	api, err := service.PreInitJiraAPI(s, params, http.MethodPost)
+	api.ReadOnly = true
+
+	return api, err
+}
+
+// See service/service.go for details
+func (s *ServicePrefetchIssues) Reset() {
+	s.keys = nil
+	s.batch = 0
+	s.issues = make(map[string][]byte)
+	s.missing = make(map[string]bool)
+}
+
+// See service/service.go for details
+func (s *ServicePrefetchIssues) HasNext() bool {
+	// The service is reset before its parameters are known
+	return s.keys == nil || s.batch*prefetchBatchSize < len(s.keys)
+}
+
+// See service/service.go for details
+func (s *ServicePrefetchIssues) GetResults() map[string]string {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServicePrefetchIssues) SetResultsFromPrevious(result map[string]string) {
+}
+
+// See service/service.go for details
+func (s *ServicePrefetchIssues) GetEndpoint(url string) string {
+	return fmt.Sprintf("%s/search", url)
+}
+
+// See service/service.go for details
+func (s *ServicePrefetchIssues) CreateRequestBody() []byte {
+	batch := s.currentBatch()
+	body, _ := json.Marshal(SearchRequest{
+		Jql:        fmt.Sprintf("key in (%s)", strings.Join(batch, ",")),
+		MaxResults: len(batch),
+		Fields:     IssueDataFields,
+		Expand:     []string{"names"},
+		// Unknown keys are reported as warnings instead of failing the whole batch
+		ValidateQuery: "warn",
+	})
+
+	return body
+}
+
+// See service/service.go for details
+func (s *ServicePrefetchIssues) JSONResponseObject() interface{} {
+	return &SearchResults{}
+}
+
+// See service/service.go for details
+func (s *ServicePrefetchIssues) PostAPICall(result interface{}) error {
+	page, ok := result.(*SearchResults)
+	if !ok {
+		return errors.New("failed to convert result of type interface{} to search results of type reading.SearchResults")
+	}
+
+	for _, issue := range page.Issues {
+		var key string
+		if err := json.Unmarshal(issue["key"], &key); err != nil {
+			return fmt.Errorf("failed to read the key of a prefetched issue: %v", err)
+		}
+
+		// Each issue is stored as it would have been returned by the issue API
+		issue["names"] = page.Names
+		body, err := json.Marshal(issue)
+		if err != nil {
+			return err
+		}
+		s.issues[key] = body
+	}
+
+	for _, w := range page.WarningMessages {
+		for _, m := range warningKey.FindAllStringSubmatch(w, -1) {
+			s.missing[m[1]] = true
+		}
+	}
+
+	s.batch++
+
+	return nil
+}
+
+func (s *ServicePrefetchIssues) Name() string {
+	return "ServicePrefetchIssues"
+}
+
+func (s *ServicePrefetchIssues) ExecuteAsLastStep(params configuration.JiraAPIResourceParameters) error {
+	return nil
+}
+
+// Returns the body of each prefetched issue by key, as the issue API returns it when asked for the IssueDataFields
+// along with their names (see IssueFieldsURL)
+func (s *ServicePrefetchIssues) Issues() map[string][]byte {
+	return s.issues
+}
+
+// Returns the keys of the list that don't exist or aren't visible
+func (s *ServicePrefetchIssues) Missing() []string {
+	var keys []string
+	for _, k := range s.keys {
+		if s.missing[k] {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// Returns the keys of the list that were found under another key, the issues having been moved to another project,
+// along with the current keys of the issues that were not requested as such
+func (s *ServicePrefetchIssues) Moved() (oldKeys []string, newKeys []string) {
+	requested := make(map[string]bool)
+	for _, k := range s.keys {
+		requested[k] = true
+
+		if _, found := s.issues[k]; !found && !s.missing[k] {
+			oldKeys = append(oldKeys, k)
+		}
+	}
+
+	for k := range s.issues {
+		if !requested[k] {
+			newKeys = append(newKeys, k)
+		}
+	}
+	sort.Strings(newKeys)
+
+	return oldKeys, newKeys
+}
+
+func (s *ServicePrefetchIssues) currentBatch() []string {
+	end := (s.batch + 1) * prefetchBatchSize
+	if end > len(s.keys) {
+		end = len(s.keys)
+	}
+
+	return s.keys[s.batch*prefetchBatchSize : end]
+}
@@ -3,7 +3,6 @@ package reading
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/assets"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
@@ -45,7 +44,7 @@ func (s *ServiceReadIssue) SetResultsFromPrevious(result map[string]string) {
 }
 
 func (s *ServiceReadIssue) GetEndpoint(url string) string {
-	return fmt.Sprintf("%s/issue/%s?expand=names", url, s.issueId)
+	return IssueURL(url, s.issueId)
 }
 
 func (s *ServiceReadIssue) CreateRequestBody() []byte {
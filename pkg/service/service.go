@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
 )
@@ -36,8 +37,40 @@ type Service interface {
 	ExecuteAsLastStep(params configuration.JiraAPIResourceParameters) error
 }
 
+// IterativeService is implemented by services that need more than one API call to complete their work, such as
+// the ones reading a paginated collection. The service is called as long as HasNext returns true.
+type IterativeService interface {
+	Service
+
+	// Resets the internal state of the service (page offset, collected values, ...) before the first call
+	Reset()
+
+	// Returns true if another API call is required. It is evaluated after Reset and after each PostAPICall.
+	HasNext() bool
+}
+
+// IdempotentService is implemented by services sending a non-idempotent request (i.e. a POST) that are able to tell
+// whether a failed attempt was nonetheless applied by Jira. Only those requests are retried.
+type IdempotentService interface {
+	Service
+
+	// Returns the check telling whether a previous attempt of the request was already applied, or nil when the
+	// service can't tell, in which case the request is never retried.
+	IdempotencyCheck(params configuration.JiraAPIResourceParameters) rest.IdempotencyCheckFN
+}
+
+// ErrorHandlingService is implemented by services for which some error responses are an expected outcome (i.e. a
+// property that was never set) rather than a failure of the call.
+type ErrorHandlingService interface {
+	Service
+
+	// Handles the error response of an API call. It returns nil when the service handled the error, in which case
+	// PostAPICall isn't called for that call and the execution goes on, or the error failing the call otherwise.
+	HandleAPIError(err *rest.HTTPError) error
+}
+
 func PreInitJiraAPI(s Service, params configuration.JiraAPIResourceParameters, httpMethod string) (rest.JiraAPI, error) {
-	api, err := rest.CreateAPIFromParams(params, s.CreateRequestBody, s.GetEndpoint, s.JSONResponseObject, httpMethod)
+	api, err := rest.CreateAPI(s.CreateRequestBody, s.GetEndpoint, s.JSONResponseObject, httpMethod)
 	if err != nil {
 		return api, err
 	}
@@ -45,30 +78,63 @@ func PreInitJiraAPI(s Service, params configuration.JiraAPIResourceParameters, h
 	return api, nil
 }
 
-func Execute(s Service, params configuration.JiraAPIResourceParameters, lastStep bool) error {
-	result, err := exec(s, params)
-
-	if err != nil {
+// Executes the service with the client: every API call of the service is authenticated and sent by that client. The
+// calls are aborted, and no further call is made, once the context is done.
+func Execute(ctx context.Context, client *rest.Client, s Service, params configuration.JiraAPIResourceParameters, lastStep bool) error {
+	if err := execAll(ctx, client, s, params); err != nil {
 		return err
 	}
 
 	if lastStep {
-		if err := s.PostAPICall(result); err != nil {
+		return s.ExecuteAsLastStep(params)
+	}
+
+	return nil
+}
+
+func execAll(ctx context.Context, client *rest.Client, s Service, params configuration.JiraAPIResourceParameters) error {
+	is, iterative := s.(IterativeService)
+	if !iterative {
+		return execOnce(ctx, client, s, params)
+	}
+
+	for is.Reset(); is.HasNext(); {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
 
-		return s.ExecuteAsLastStep(params)
+		if err := execOnce(ctx, client, s, params); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func execOnce(ctx context.Context, client *rest.Client, s Service, params configuration.JiraAPIResourceParameters) error {
+	result, err := exec(ctx, client, s, params)
+	if httpErr, ok := err.(*rest.HTTPError); ok {
+		if es, handling := s.(ErrorHandlingService); handling {
+			return es.HandleAPIError(httpErr)
+		}
+	}
+	if err != nil {
+		return err
 	}
 
 	return s.PostAPICall(result)
 }
 
-func exec(s Service, params configuration.JiraAPIResourceParameters) (interface{}, error) {
+func exec(ctx context.Context, client *rest.Client, s Service, params configuration.JiraAPIResourceParameters) (interface{}, error) {
 	api, err := s.InitJiraAPI(params)
 
 	if err != nil {
 		return nil, err
-	} else {
-		return api.Call()
 	}
+
+	if is, ok := s.(IdempotentService); ok {
+		api.IdempotencyCheck = is.IdempotencyCheck(params)
+	}
+
+	return api.Call(ctx, client)
 }
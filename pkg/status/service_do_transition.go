@@ -1,6 +1,7 @@
 package status
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
@@ -10,8 +11,9 @@ import (
 )
 
 type ServiceDoTransition struct {
-	issueId    string
-	statusName string
+	issueId     string
+	statusName  string
+	transitions Transitions
 }
 
 func (s *ServiceDoTransition) InitJiraAPI(params configuration.JiraAPIResourceParameters) (rest.JiraAPI, error) {
@@ -45,7 +47,7 @@ func (s *ServiceDoTransition) CreateRequestBody() []byte {
 
 	var tId string
 	// Lookup id from stored status name
-	for _, tVal := range TransitionsSlice.Transitions {
+	for _, tVal := range s.transitions.Transitions {
 		if tVal.To.Name == s.statusName {
 			tId = tVal.Id
 		}
@@ -71,6 +73,33 @@ func (s *ServiceDoTransition) PostAPICall(result interface{}) error {
 	return nil
 }
 
+// A transition was applied if the issue is already in the status it leads to
+func (s *ServiceDoTransition) IdempotencyCheck(params configuration.JiraAPIResourceParameters) rest.IdempotencyCheckFN {
+	return func(ctx context.Context, client *rest.Client) (bool, error) {
+		issue := &struct {
+			Fields struct {
+				Status struct {
+					Name string `json:"name"`
+				} `json:"status"`
+			} `json:"fields"`
+		}{}
+
+		endpoint := func(url string) string {
+			return fmt.Sprintf("%s/issue/%s?fields=status", url, s.issueId)
+		}
+		api, err := rest.CreateAPI(nil, endpoint, func() interface{} { return issue }, http.MethodGet)
+		if err != nil {
+			return false, err
+		}
+
+		if _, err := api.Call(ctx, client); err != nil {
+			return false, err
+		}
+
+		return issue.Fields.Status.Name == s.statusName, nil
+	}
+}
+
 func (s *ServiceDoTransition) Name() string {
 	return "ServiceDoTransition"
 }
@@ -82,3 +111,8 @@ func (s *ServiceDoTransition) ExecuteAsLastStep(params configuration.JiraAPIReso
 func (s *ServiceDoTransition) OverwriteTransitionName(name string) {
 	s.statusName = name
 }
+
+// Sets the transitions available from the current status of the issue, among which the one to perform is looked up
+func (s *ServiceDoTransition) SetTransitions(transitions Transitions) {
+	s.transitions = transitions
+}
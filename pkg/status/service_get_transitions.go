@@ -10,7 +10,8 @@ import (
 )
 
 type ServiceGetTransitions struct {
-	issueId string
+	issueId     string
+	transitions Transitions
 }
 
 func (s *ServiceGetTransitions) InitJiraAPI(params configuration.JiraAPIResourceParameters) (rest.JiraAPI, error) {
@@ -48,11 +49,16 @@ func (s *ServiceGetTransitions) PostAPICall(result interface{}) error {
 	if transitions, ok := result.(*Transitions); !ok {
 		return errors.New("failed to convert result of type interface{} to issue of type reading.Issue")
 	} else {
-		TransitionsSlice = *transitions
+		s.transitions = *transitions
 	}
 	return nil
 }
 
+// Returns the transitions available from the current status of the issue
+func (s *ServiceGetTransitions) Transitions() Transitions {
+	return s.transitions
+}
+
 func (s *ServiceGetTransitions) Name() string {
 	return "ServiceGetTransitions"
 }
@@ -14,5 +14,3 @@ type Transitions struct {
 	Expand      string       `json:"expand"`
 	Transitions []Transition `json:"transitions"`
 }
-
-var TransitionsSlice Transitions
@@ -0,0 +1,67 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/configuration"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/helpers"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/http/rest"
+	"github.com/TurnsCoffeeIntoScripts/jira-api-issue-resource/pkg/service"
+	"net/http"
+)
+
+// The ServiceGetMyself struct implements the service.Service interface. It fetches the user the resource is
+// connected as.
+type ServiceGetMyself struct {
+	myself User
+}
+
+// See service/service.go for details
+func (s *ServiceGetMyself) InitJiraAPI(params configuration.JiraAPIResourceParameters) (rest.JiraAPI, error) {
+	return service.PreInitJiraAPI(s, params, http.MethodGet)
+}
+
+// See service/service.go for details
+func (s *ServiceGetMyself) GetResults() map[string]string {
+	var m = make(map[string]string)
+	m[helpers.MyselfIdKey] = s.myself.Id()
+	return m
+}
+
+// See service/service.go for details
+func (s *ServiceGetMyself) SetResultsFromPrevious(result map[string]string) {
+}
+
+// See service/service.go for details
+func (s *ServiceGetMyself) GetEndpoint(url string) string {
+	return fmt.Sprintf("%s/myself", url)
+}
+
+// See service/service.go for details
+func (s *ServiceGetMyself) CreateRequestBody() []byte {
+	return nil
+}
+
+// See service/service.go for details
+func (s *ServiceGetMyself) JSONResponseObject() interface{} {
+	return &User{}
+}
+
+// See service/service.go for details
+func (s *ServiceGetMyself) PostAPICall(result interface{}) error {
+	if myself, ok := result.(*User); !ok {
+		return errors.New("failed to convert result of type interface{} to user of type user.User")
+	} else {
+		s.myself = *myself
+	}
+
+	return nil
+}
+
+func (s *ServiceGetMyself) Name() string {
+	return "ServiceGetMyself"
+}
+
+func (s *ServiceGetMyself) ExecuteAsLastStep(params configuration.JiraAPIResourceParameters) error {
+	return nil
+}
@@ -0,0 +1,29 @@
+// Package user provides the representation of a Jira user and a service to identify the user the resource is
+// connected as.
+package user
+
+// This struct is a representation of a Jira user. The account id is only returned by Jira Cloud while the name and
+// key are only returned by Jira Server/Data Center.
+type User struct {
+	AccountId    string `json:"accountId,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Key          string `json:"key,omitempty"`
+	DisplayName  string `json:"displayName,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+// Returns true if the user is identified by the value, whether it is the account id, the name, the key, the display
+// name or the email address of the user.
+func (u *User) Is(value string) bool {
+	return value != "" && (value == u.AccountId || value == u.Name || value == u.Key ||
+		value == u.DisplayName || value == u.EmailAddress)
+}
+
+// Returns the identifier of the user: the account id on Jira Cloud and the name on Jira Server/Data Center.
+func (u *User) Id() string {
+	if u.AccountId != "" {
+		return u.AccountId
+	}
+
+	return u.Name
+}